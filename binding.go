@@ -0,0 +1,383 @@
+package echoApi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// BindMode 请求体绑定模式
+type BindMode int
+
+const (
+	BindModeDefault BindMode = iota // 跟随 Global 配置或 DefaultBindMode
+	BindModeStrict                  // 严格模式：body 格式错误、字段类型错误返回 400，未注册的 Content-Type 返回 415
+	BindModeLenient                 // 宽松模式：忽略 body 的格式和类型错误，能解析的字段照常绑定，未注册的 Content-Type 按 JSON 解码
+)
+
+// DefaultBindMode 全局默认的请求体绑定模式
+var DefaultBindMode = BindModeStrict
+
+// DefaultDisallowUnknownFields 全局默认是否拒绝 body 中未声明的字段
+var DefaultDisallowUnknownFields = false
+
+// BindError 请求体绑定错误，作为 BaseHttpError.Details 返回给客户端
+type BindError struct {
+	Field  string `json:"field,omitempty" xml:"field,omitempty"`   // 出错的字段（json 名）
+	Offset int64  `json:"offset,omitempty" xml:"offset,omitempty"` // 错误在 body 中的字节偏移
+	Reason string `json:"reason" xml:"reason"`
+
+	reasonCode string // Reason 的错误码（如 BIND_UNKNOWN_FIELD），响应时按 Accept-Language 翻译
+	reasonArgs []any
+}
+
+func (e *BindError) Error() string {
+	return e.message([]string{DefaultLanguage})
+}
+
+// withReason 按错误码设置 Reason
+func (e *BindError) withReason(code string, args ...any) *BindError {
+	e.reasonCode = code
+	e.reasonArgs = args
+	e.Reason = errorMessage(code, args...)
+	return e
+}
+
+// reason 按语言优先级翻译 Reason
+func (e *BindError) reason(langs []string) string {
+	if e.reasonCode != "" {
+		if msg, ok := translate(e.reasonCode, langs, e.reasonArgs...); ok {
+			return msg
+		}
+	}
+	return e.Reason
+}
+
+// localize 返回 Reason 按语言优先级翻译后的副本
+func (e *BindError) localize(langs []string) *BindError {
+	res := *e
+	res.Reason = e.reason(langs)
+	return &res
+}
+
+// message 按语言优先级生成错误消息
+func (e *BindError) message(langs []string) string {
+	reason := e.reason(langs)
+	code, args := "BIND_BODY", []any{reason}
+	switch {
+	case e.Field != "" && e.Offset > 0:
+		code, args = "BIND_FIELD_OFFSET", []any{e.Field, e.Offset, reason}
+	case e.Field != "":
+		code, args = "BIND_FIELD", []any{e.Field, reason}
+	case e.Offset > 0:
+		code, args = "BIND_OFFSET", []any{e.Offset, reason}
+	}
+	msg, _ := translate(code, langs, args...)
+	return msg
+}
+
+// bindOptions 单次请求的绑定选项（由路由配置和全局配置计算得到）
+type bindOptions struct {
+	Strict bool  // 严格模式
+	Codec  Codec // 按 Content-Type 选择的解码器，nil 时使用 JSON
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// bodyBinding body 绑定信息（在 Register 时构建一次）
+// 参数结构体实现了 json.Unmarshaler，或所有导出字段都从 body 绑定时，直接解码到参数结构体；
+// 否则解码到只包含 body 字段的影子结构体再按索引复制，避免 query/param 字段被 body 中的同名 key 填充。
+// 两种方式都不经过 map[string]interface{}，int64 等大整数不会丢失精度
+// body 字段统一通过 json 标签声明，XML 按 xml 标签（没有时为字段名）、MessagePack 按 json 标签匹配
+type bodyBinding struct {
+	Direct     bool         // 直接解码到参数结构体
+	Shadow     reflect.Type // 影子结构体类型（Direct 为 false 时有效）
+	FieldIndex [][]int      // 影子结构体第 i 个字段对应参数结构体的索引路径
+	Names      []string     // body 字段的 json 名
+}
+
+// buildBodyBinding 构建 body 绑定信息，没有 body 字段时返回 nil
+func buildBodyBinding(t reflect.Type) *bodyBinding {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	var indexes [][]int
+	allBody := collectBodyFields(t, nil, &fields, &indexes)
+
+	// 自定义 UnmarshalJSON 的结构体总是直接解码，由其自行处理 body
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return &bodyBinding{Direct: true, Names: bodyFieldNames(fields)}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	if allBody {
+		return &bodyBinding{Direct: true, Names: bodyFieldNames(fields)}
+	}
+
+	shadowFields := make([]reflect.StructField, len(fields))
+	for i, field := range fields {
+		shadowFields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: field.Type,
+			Tag:  shadowFieldTag(field),
+		}
+	}
+	return &bodyBinding{
+		Shadow:     reflect.StructOf(shadowFields),
+		FieldIndex: indexes,
+		Names:      bodyFieldNames(fields),
+	}
+}
+
+// collectBodyFields 收集 body 字段（与 encoding/json 一样展开匿名结构体），返回是否所有导出字段都从 body 绑定
+func collectBodyFields(t reflect.Type, index []int, fields *[]reflect.StructField, indexes *[][]int) bool {
+	allBody := true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+		jsonTag := field.Tag.Get("json")
+
+		switch {
+		case jsonTag == "-":
+			continue
+		case field.Anonymous && jsonTag == "" && field.Type.Kind() == reflect.Struct:
+			if !collectBodyFields(field.Type, fieldIndex, fields, indexes) {
+				allBody = false
+			}
+		case !field.IsExported():
+			continue
+		case isBodyField(field):
+			*fields = append(*fields, field)
+			*indexes = append(*indexes, fieldIndex)
+		default:
+			allBody = false
+		}
+	}
+	return allBody
+}
+
+// shadowFieldTag 影子字段保留原始标签，json 名为空时（如 json:",omitempty"）补上原字段名
+// 没有 xml 标签时补上原字段名，使 XML 解码与直接解码到参数结构体的行为一致
+func shadowFieldTag(field reflect.StructField) reflect.StructTag {
+	tag := string(field.Tag)
+	jsonTag := field.Tag.Get("json")
+	if name, opts, hasOpts := strings.Cut(jsonTag, ","); name == "" {
+		newTag := field.Name
+		if hasOpts {
+			newTag += "," + opts
+		}
+		tag = strings.Replace(tag, `json:"`+jsonTag+`"`, `json:"`+newTag+`"`, 1)
+	}
+	if _, ok := field.Tag.Lookup("xml"); !ok {
+		tag += ` xml:"` + field.Name + `"`
+	}
+	return reflect.StructTag(tag)
+}
+
+func bodyFieldNames(fields []reflect.StructField) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// decode 使用 codec 将 body 解码到参数结构体
+func (b *bodyBinding) decode(codec Codec, body []byte, target reflect.Value) error {
+	if b.Direct {
+		return codec.Unmarshal(body, target.Addr().Interface())
+	}
+
+	shadow := reflect.New(b.Shadow)
+	err := codec.Unmarshal(body, shadow.Interface())
+	// 类型错误时 encoding/json 仍会继续解码其余字段，宽松模式下需要保留这些字段
+	shadow = shadow.Elem()
+	for i, index := range b.FieldIndex {
+		fieldByIndexAlloc(target, index).Set(shadow.Field(i))
+	}
+	return err
+}
+
+// resolveBindMode 计算路由最终的绑定模式
+func resolveBindMode(mode BindMode) BindMode {
+	if mode == BindModeDefault {
+		mode = DefaultBindMode
+	}
+	if mode == BindModeDefault {
+		mode = BindModeStrict
+	}
+	return mode
+}
+
+// bodyFieldSet body 中允许出现的字段，值为该字段（对象或对象数组）内允许出现的字段，nil 表示不检查其内部
+type bodyFieldSet map[string]bodyFieldSet
+
+// add 添加字段，多个参数声明同名字段时合并其内部允许的字段
+func (s bodyFieldSet) add(name string, nested bodyFieldSet) {
+	old, ok := s[name]
+	switch {
+	case !ok:
+		s[name] = nested
+	case old == nil || nested == nil:
+		s[name] = nil
+	default:
+		merged := make(bodyFieldSet, len(old)+len(nested))
+		for key, value := range old {
+			merged.add(key, value)
+		}
+		for key, value := range nested {
+			merged.add(key, value)
+		}
+		s[name] = merged
+	}
+}
+
+// buildKnownBodyFields 构建 body 中允许出现的字段（所有参数的并集）
+// 除 body 字段外，query/param/header/cookie/form 字段的名字也视为已声明：这些 key 出现在 body 中时忽略，不算未声明
+func buildKnownBodyFields(params []ParamBinding) bodyFieldSet {
+	known := make(bodyFieldSet)
+	seen := make(map[reflect.Type]bodyFieldSet)
+	for i := range params {
+		param := &params[i]
+		if param.Body != nil {
+			var fields []reflect.StructField
+			var indexes [][]int
+			collectBodyFields(param.ElemType, nil, &fields, &indexes)
+			for j, name := range bodyFieldNames(fields) {
+				known.add(name, nestedBodyFields(fields[j].Type, seen))
+			}
+		}
+		for _, name := range sourceFieldNames(param) {
+			known.add(name, nil)
+		}
+	}
+	return known
+}
+
+// nestedBodyFields 按 encoding/json 的规则计算类型内允许出现的字段，不是对象的类型返回 nil
+// map 以及自定义解码（json.Unmarshaler、encoding.TextUnmarshaler、time.Time）的类型不检查
+func nestedBodyFields(t reflect.Type, seen map[reflect.Type]bodyFieldSet) bodyFieldSet {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if !isNestedQueryStruct(t) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	if fields, ok := seen[t]; ok {
+		return fields
+	}
+	fields := make(bodyFieldSet)
+	// 先记录再展开，自引用的类型共用同一个集合
+	seen[t] = fields
+	appendNestedBodyFields(fields, t, seen)
+	return fields
+}
+
+func appendNestedBodyFields(fields bodyFieldSet, t reflect.Type, seen map[reflect.Type]bodyFieldSet) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(jsonTag, ",")
+		fieldType := indirectType(field.Type)
+
+		switch {
+		case jsonTag == "-":
+			continue
+		case field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct && (field.IsExported() || isBindableEmbedded(field)):
+			appendNestedBodyFields(fields, fieldType, seen)
+		case !field.IsExported():
+			continue
+		default:
+			if name == "" {
+				name = field.Name
+			}
+			fields.add(name, nestedBodyFields(field.Type, seen))
+		}
+	}
+}
+
+// sourceFieldNames 参数中 query/param/header/cookie/form 字段的顶层名字
+func sourceFieldNames(param *ParamBinding) []string {
+	var names []string
+	for _, info := range param.QueryFields {
+		name, _, _ := strings.Cut(info.Key, ".")
+		names = append(names, name)
+	}
+	for _, info := range param.SourceFields {
+		for _, name := range []string{info.Header, info.Cookie, info.Form} {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	if param.ElemType != nil && param.ElemType.Kind() == reflect.Struct {
+		for i := 0; i < param.ElemType.NumField(); i++ {
+			if name, _ := parseSourceTag(param.ElemType.Field(i).Tag.Get("param")); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// newBodyDecodeError 将 encoding/json 的错误转换为 BindError
+func newBodyDecodeError(err error) *BindError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &BindError{Offset: syntaxErr.Offset, Reason: syntaxErr.Error()}
+	case errors.As(err, &typeErr):
+		bindErr := &BindError{Field: typeErr.Field, Offset: typeErr.Offset}
+		return bindErr.withReason("BIND_TYPE_MISMATCH", typeErr.Type.String(), typeErr.Value)
+	}
+	return &BindError{Reason: err.Error()}
+}
+
+// checkUnknownBodyFields 检查 body 中是否有未声明的字段，嵌套对象和对象数组逐层检查
+// body 无法解码为对象时（包括 XML 这类不支持解码到 map 的编码）不做检查，格式错误由各参数的绑定过程处理
+func checkUnknownBodyFields(codec Codec, body []byte, known bodyFieldSet) error {
+	var bodyMap map[string]any
+	if err := codec.Unmarshal(body, &bodyMap); err != nil {
+		return nil
+	}
+	if bindErr := known.check(bodyMap, ""); bindErr != nil {
+		return bindErr
+	}
+	return nil
+}
+
+// check 检查 value 中的对象是否只包含允许的字段，返回第一个未声明的字段（字段路径如 items[0].name）
+func (s bodyFieldSet) check(value any, path string) *BindError {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			nested, ok := s[key]
+			if !ok {
+				bindErr := &BindError{Field: joinFieldPath(path, key)}
+				return bindErr.withReason("BIND_UNKNOWN_FIELD")
+			}
+			if nested != nil {
+				if bindErr := nested.check(child, joinFieldPath(path, key)); bindErr != nil {
+					return bindErr
+				}
+			}
+		}
+	case []any:
+		for i, item := range v {
+			if bindErr := s.check(item, fmt.Sprintf("%s[%d]", path, i)); bindErr != nil {
+				return bindErr
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package echoApi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// DefaultMaxBodyBytes 全局默认的请求体大小上限（字节），<= 0 表示不限制
+// 路由可以通过 RouteBuilder.MaxBodyBytes 单独配置
+var DefaultMaxBodyBytes int64 = 10 << 20
+
+var (
+	readerType     = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	readCloserType = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+)
+
+// isReaderParam 判断参数是否为 io.Reader / io.ReadCloser，这类参数直接接收请求体流
+func isReaderParam(t reflect.Type) bool {
+	return t == readerType || t == readCloserType
+}
+
+// resolveMaxBodyBytes 计算路由最终的请求体大小上限，0 表示使用 DefaultMaxBodyBytes，< 0 表示不限制
+func resolveMaxBodyBytes(limit int64) int64 {
+	if limit == 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// isBodyTooLarge 判断错误是否由请求体超出 MaxBodyBytes 引起
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// newBodyTooLargeError 请求体超出上限时返回的 413 错误
+func newBodyTooLargeError(limit int64, requestId string) BaseHttpError {
+	htperr := NewError("BODY_TOO_LARGE", limit)
+	htperr.RequestId = requestId
+	return htperr
+}
@@ -0,0 +1,147 @@
+package echoApi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ClientIPHeaders 解析客户端 IP 的请求头，按顺序使用第一个存在的请求头
+// 只有连接的对端是受信任的代理（见 SetTrustedProxies）时才会读取
+var ClientIPHeaders = []string{"Forwarded", echo.HeaderXForwardedFor, echo.HeaderXRealIP}
+
+// trustedProxies 受信任的代理网段（使用读写锁保护），为空时不信任任何代理
+var (
+	trustedProxies   []netip.Prefix
+	trustedProxiesMu sync.RWMutex
+)
+
+// SetTrustedProxies 设置受信任的代理，支持 CIDR（如 10.0.0.0/8）和单个 IP，替换之前的设置
+// 未设置时客户端 IP 为连接的对端地址，不读取任何请求头（客户端可以伪造这些请求头）
+func SetTrustedProxies(proxies ...string) error {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return fmt.Errorf("受信任代理格式错误: %w", err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return fmt.Errorf("受信任代理格式错误: %w", err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	trustedProxiesMu.Lock()
+	defer trustedProxiesMu.Unlock()
+	trustedProxies = prefixes
+	return nil
+}
+
+// isTrustedProxy 地址是否属于受信任的代理
+func isTrustedProxy(addr netip.Addr) bool {
+	trustedProxiesMu.RLock()
+	defer trustedProxiesMu.RUnlock()
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 请求的客户端 IP：BaseErrorMiddleware 解析后保存在 context 中（见 RemoteIP），没有时按 ResolveClientIP 解析
+func ClientIP(c echo.Context) string {
+	if ip := RemoteIP(Context(c)); ip != "" {
+		return ip
+	}
+	return ResolveClientIP(c.Request())
+}
+
+// ResolveClientIP 解析客户端 IP
+// 连接的对端不是受信任的代理时直接返回对端地址；否则按 ClientIPHeaders 取代理链，从右向左跳过受信任的代理，
+// 返回第一个不受信任的地址（全部受信任时返回最左边的地址，遇到 unknown 等无法解析的地址时返回其右边的一跳）
+func ResolveClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, ok := parseHop(host)
+	if !ok {
+		return host
+	}
+	if !isTrustedProxy(remote) {
+		return remote.String()
+	}
+
+	for _, name := range ClientIPHeaders {
+		values := r.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		var chain []string
+		switch http.CanonicalHeaderKey(name) {
+		case "Forwarded":
+			chain = parseForwardedFor(strings.Join(values, ","))
+		default:
+			chain = ParseXForwardedFor(strings.Join(values, ","))
+		}
+		if len(chain) == 0 {
+			continue
+		}
+
+		ip := remote
+		for i := len(chain) - 1; i >= 0; i-- {
+			addr, ok := parseHop(chain[i])
+			if !ok {
+				break
+			}
+			ip = addr
+			if !isTrustedProxy(addr) {
+				break
+			}
+		}
+		return ip.String()
+	}
+	return remote.String()
+}
+
+// parseForwardedFor 解析 RFC 7239 Forwarded 请求头中各跳的 for 参数，如
+// Forwarded: for=192.0.2.60;proto=http, for="[2001:db8:cafe::17]:4711"
+func parseForwardedFor(forwarded string) []string {
+	var res []string
+	for _, element := range strings.Split(forwarded, ",") {
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "for") {
+				res = append(res, strings.Trim(strings.TrimSpace(value), `"`))
+			}
+		}
+	}
+	return res
+}
+
+// parseHop 解析代理链中的一跳，支持带端口（1.2.3.4:80、[::1]:80）和方括号的 IPv6 地址
+func parseHop(hop string) (netip.Addr, bool) {
+	hop = strings.TrimSpace(hop)
+	if addrPort, err := netip.ParseAddrPort(hop); err == nil {
+		return addrPort.Addr().Unmap(), true
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(hop, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,81 @@
+package echoApi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveClientIP(t *testing.T) {
+	t.Cleanup(func() { _ = SetTrustedProxies() })
+
+	assert.Equal(t, []string{"1.1.1.1", "10.0.0.2"}, ParseXForwardedFor(" 1.1.1.1 ,, 10.0.0.2 "))
+	assert.Error(t, SetTrustedProxies("10.0.0.0/33"))
+	assert.Error(t, SetTrustedProxies("proxy"))
+
+	request := func(remoteAddr string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		return req
+	}
+	xff := map[string]string{echo.HeaderXForwardedFor: "6.6.6.6, 1.1.1.1, 10.0.0.2"}
+
+	// 未配置受信任代理时不读取请求头
+	assert.Equal(t, "10.0.0.1", ResolveClientIP(request("10.0.0.1:1234", xff)))
+
+	assert.NoError(t, SetTrustedProxies("10.0.0.0/8", "::1"))
+	cases := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"对端不受信任", "2.2.2.2:1234", xff, "2.2.2.2"},
+		{"从右向左跳过受信任代理", "10.0.0.1:1234", xff, "1.1.1.1"},
+		{"全部受信任", "10.0.0.1:1234", map[string]string{echo.HeaderXForwardedFor: "10.1.1.1, 10.0.0.2"}, "10.1.1.1"},
+		{"无法解析的地址", "10.0.0.1:1234", map[string]string{echo.HeaderXForwardedFor: "1.1.1.1, unknown, 10.0.0.2"}, "10.0.0.2"},
+		{"X-Real-IP", "[::1]:1234", map[string]string{echo.HeaderXRealIP: "3.3.3.3"}, "3.3.3.3"},
+		{"Forwarded 优先", "10.0.0.1:1234", map[string]string{
+			"Forwarded":              `for=6.6.6.6, for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.2`,
+			echo.HeaderXForwardedFor: "5.5.5.5",
+		}, "2001:db8:cafe::17"},
+		{"没有请求头", "10.0.0.1:1234", nil, "10.0.0.1"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, ResolveClientIP(request(tc.remoteAddr, tc.headers)), tc.name)
+	}
+}
+
+type testIPReq struct {
+	IP string `ip:"client"`
+}
+
+func testClientIP(c echo.Context, req *testIPReq) (map[string]any, error) {
+	return map[string]any{"ip": req.IP, "ctx": RemoteIP(Context(c)), "real": c.RealIP()}, nil
+}
+
+func TestClientIPParam(t *testing.T) {
+	t.Cleanup(func() { _ = SetTrustedProxies() })
+	assert.NoError(t, SetTrustedProxies("10.0.0.0/8"))
+
+	srv := NewServer("")
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/ip"}, testClientIP))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "6.6.6.6, 1.1.1.1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var res map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, map[string]any{"ip": "1.1.1.1", "ctx": "1.1.1.1", "real": "1.1.1.1"}, res["data"])
+}
@@ -0,0 +1,374 @@
+package echoApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec 请求体/响应体编解码器
+// 请求按 Content-Type 选择解码器，响应按 Accept 选择编码器
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// ErrUnsupportedType 编解码器不支持该类型（如 protobuf 编码器收到非 protobuf 消息），响应会回退到 JSON
+var ErrUnsupportedType = errors.New("codec: unsupported type")
+
+// MIMEApplicationXMsgpack MessagePack 的另一个常用 MIME 类型
+const MIMEApplicationXMsgpack = "application/x-msgpack"
+
+// codecs 编解码器注册表（按 MIME 类型，使用读写锁保护）
+var (
+	codecs = map[string]Codec{
+		echo.MIMEApplicationJSON:    JSONCodec{},
+		echo.MIMEApplicationXML:     XMLCodec{},
+		echo.MIMETextXML:            XMLCodec{},
+		echo.MIMEApplicationMsgpack: MsgpackCodec{},
+		MIMEApplicationXMsgpack:     MsgpackCodec{},
+	}
+	codecsMu sync.RWMutex
+)
+
+// RegisterCodec 注册或替换某个 MIME 类型的编解码器
+// 例如注册 protobuf：RegisterCodec(echo.MIMEApplicationProtobuf, ProtobufCodec{...})
+//
+// 内置 JSON、XML、MessagePack，CBOR 等其他格式需要引入对应的库后自行注册，如使用 github.com/fxamacker/cbor/v2：
+//
+//	type CBORCodec struct{}
+//
+//	func (CBORCodec) Marshal(v any) ([]byte, error)      { return cbor.Marshal(v) }
+//	func (CBORCodec) Unmarshal(data []byte, v any) error { return cbor.Unmarshal(data, v) }
+//
+//	echoApi.RegisterCodec("application/cbor", CBORCodec{})
+//
+// 注册后请求按 Content-Type: application/cbor 解码，Accept 包含 application/cbor 时按 CBOR 编码响应
+func RegisterCodec(mimeType string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[normalizeMIME(mimeType)] = codec
+}
+
+// GetCodec 获取某个 MIME 类型的编解码器
+func GetCodec(mimeType string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	codec, ok := codecs[normalizeMIME(mimeType)]
+	return codec, ok
+}
+
+// normalizeMIME 去掉参数（如 ;charset=utf-8）并转为小写
+func normalizeMIME(mimeType string) string {
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+// codecForContentType 按请求的 Content-Type 选择解码器，未设置 Content-Type 时使用 JSON
+func codecForContentType(contentType string) (Codec, bool) {
+	if normalizeMIME(contentType) == "" {
+		return JSONCodec{}, true
+	}
+	return GetCodec(contentType)
+}
+
+// qualityValue Accept 类请求头中的一项
+type qualityValue struct {
+	Value   string
+	Quality float64
+}
+
+// parseQualityList 解析 Accept、Accept-Language 等带 q 值的请求头，按 q 值从高到低排序（q 相同时保持原顺序）
+// 格式错误的 q 值按 1 处理，q=0 的项保留，由调用方决定是否排除；值保留原始大小写，由调用方按需忽略大小写比较
+func parseQualityList(header string) []qualityValue {
+	var res []qualityValue
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil && f >= 0 && f <= 1 {
+				q = f
+			}
+		}
+		res = append(res, qualityValue{Value: value, Quality: q})
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Quality > res[j].Quality
+	})
+	return res
+}
+
+// negotiateCodec 按 Accept 选择响应编码器，没有可用的编码器时使用 JSON
+func negotiateCodec(accept string) (string, Codec) {
+	if accept == "" {
+		return echo.MIMEApplicationJSON, JSONCodec{}
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	for _, item := range parseQualityList(accept) {
+		if item.Quality == 0 {
+			continue
+		}
+		value := strings.ToLower(item.Value)
+		if value == "*/*" {
+			break
+		}
+		if codec, ok := codecs[value]; ok {
+			return value, codec
+		}
+		// 结构化后缀，如 application/vnd.myapp.v2+json 按 application/json 编码
+		if _, suffix, ok := strings.Cut(value, "+"); ok {
+			if codec, ok := codecs["application/"+suffix]; ok {
+				return "application/" + suffix, codec
+			}
+		}
+		// application/* 这类通配只匹配 JSON，避免随机选中某个二进制编码
+		if prefix, ok := strings.CutSuffix(value, "/*"); ok && prefix == "application" {
+			break
+		}
+	}
+	return echo.MIMEApplicationJSON, JSONCodec{}
+}
+
+// writeResponse 按 Accept 协商编码器写出响应体
+// 编码器不支持响应体类型时（如 protobuf 编码器收到 map 响应）回退到 JSON
+// ProblemDetails 响应使用 problem+json 等 MIME 类型
+func writeResponse(c echo.Context, code int, v any) error {
+	if p, ok := v.(ProblemDetails); ok {
+		return writeProblem(c, code, p)
+	}
+	mimeType, codec := negotiateCodec(c.Request().Header.Get(echo.HeaderAccept))
+	if _, ok := codec.(JSONCodec); ok {
+		return c.JSON(code, v)
+	}
+
+	data, err := codec.Marshal(v)
+	if errors.Is(err, ErrUnsupportedType) {
+		return c.JSON(code, v)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, mimeType, data)
+}
+
+// JSONCodec JSON 编解码器（默认）
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// XMLCodec XML 编解码器
+// map 类型的响应体（如 BaseHttpResponse、BaseHttpError）编码为 <response> 下按 key 排序的子元素，
+// 嵌套的 map 和切片（包括 []map[string]any）按反射逐层编码；无法编码为 XML 时返回 ErrUnsupportedType，响应回退到 JSON
+type XMLCodec struct{}
+
+func (XMLCodec) Marshal(v any) ([]byte, error) {
+	if !isXMLContainer(reflect.ValueOf(v)) {
+		data, err := xml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+		}
+		return data, nil
+	}
+
+	// 顶层切片（如 Bare 信封的列表数据）编码为 <response><item>...</item></response>
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	start := xml.StartElement{Name: xml.Name{Local: "response"}}
+	value := any(xmlValue{v})
+	if rv := reflect.Indirect(reflect.ValueOf(v)); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		value = struct {
+			Items []xmlValue `xml:"item"`
+		}{xmlItems(rv)}
+	}
+	if err := enc.EncodeElement(value, start); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (XMLCodec) Unmarshal(data []byte, v any) error {
+	return xml.Unmarshal(data, v)
+}
+
+// xmlValue 让 map（key 为字符串）及包含 map 的切片可以编码为 XML（encoding/xml 不支持 map）
+// 其他类型交给 encoding/xml
+type xmlValue struct{ v any }
+
+func (x xmlValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	rv := reflect.ValueOf(x.v)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.Map && isXMLContainer(rv):
+		return encodeXMLMap(e, start, rv)
+	case isXMLContainer(rv):
+		// 与 encoding/xml 一致，切片的每个元素重复 start 元素
+		return encodeXMLSlice(e, start, rv)
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return e.EncodeElement(rv.Interface(), start)
+}
+
+// isXMLContainer 是否为需要逐层编码的类型：key 为字符串的 map，或切片、数组（[]byte 除外）
+func isXMLContainer(rv reflect.Value) bool {
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		return rv.Type().Key().Kind() == reflect.String
+	case reflect.Slice, reflect.Array:
+		return rv.Type().Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// encodeXMLMap 编码为 start 下按 key 排序的子元素
+func encodeXMLMap(e *xml.Encoder, start xml.StartElement, rv reflect.Value) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, rv.Len())
+	values := make(map[string]reflect.Value, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		keys = append(keys, key)
+		values[key] = iter.Value()
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := values[key]
+		if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+			continue
+		}
+		if err := e.EncodeElement(xmlValue{v.Interface()}, xml.StartElement{Name: xml.Name{Local: key}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// xmlItems 切片的每个元素
+func xmlItems(rv reflect.Value) []xmlValue {
+	items := make([]xmlValue, rv.Len())
+	for i := range items {
+		items[i] = xmlValue{rv.Index(i).Interface()}
+	}
+	return items
+}
+
+// encodeXMLSlice 每个元素编码为一个 start 元素
+func encodeXMLSlice(e *xml.Encoder, start xml.StartElement, rv reflect.Value) error {
+	for i := 0; i < rv.Len(); i++ {
+		if err := e.EncodeElement(xmlValue{rv.Index(i).Interface()}, start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MsgpackCodec MessagePack 编解码器
+// 与 JSON 共用 json 标签作为字段名，同一个参数结构体可以同时接收两种编码
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// ProtoMessage protobuf 生成代码中消息类型都实现的方法集（避免 echoApi 直接依赖 protobuf 库）
+type ProtoMessage interface {
+	ProtoMessage()
+	Reset()
+	String() string
+}
+
+// ProtobufCodec protobuf 编解码器扩展点，由使用方注入 proto.Marshal / proto.Unmarshal：
+//
+//	echoApi.RegisterCodec(echo.MIMEApplicationProtobuf, echoApi.ProtobufCodec{
+//		MarshalFunc: func(m echoApi.ProtoMessage) ([]byte, error) {
+//			return proto.Marshal(m.(proto.Message))
+//		},
+//		UnmarshalFunc: func(data []byte, m echoApi.ProtoMessage) error {
+//			return proto.Unmarshal(data, m.(proto.Message))
+//		},
+//	})
+//
+// 请求参数需要是 protobuf 消息类型；响应体只有是 protobuf 消息时才会编码为 protobuf（HttpResponse.GetResponse 返回消息），否则回退到 JSON
+type ProtobufCodec struct {
+	MarshalFunc   func(m ProtoMessage) ([]byte, error)
+	UnmarshalFunc func(data []byte, m ProtoMessage) error
+}
+
+func (p ProtobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(ProtoMessage)
+	if !ok || p.MarshalFunc == nil {
+		return nil, ErrUnsupportedType
+	}
+	return p.MarshalFunc(m)
+}
+
+func (p ProtobufCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(ProtoMessage)
+	if !ok || p.UnmarshalFunc == nil {
+		return ErrUnsupportedType
+	}
+	return p.UnmarshalFunc(data, m)
+}
@@ -0,0 +1,180 @@
+package echoApi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQualityList(t *testing.T) {
+	got := parseQualityList("text/html;q=0.5, application/msgpack , application/json;q=0.9,*/*;q=0, bad;q=x")
+	assert.Equal(t, []qualityValue{
+		{Value: "application/msgpack", Quality: 1},
+		{Value: "bad", Quality: 1},
+		{Value: "application/json", Quality: 0.9},
+		{Value: "text/html", Quality: 0.5},
+		{Value: "*/*", Quality: 0},
+	}, got)
+}
+
+func TestNegotiateCodec(t *testing.T) {
+	cases := map[string]string{
+		"":                                    echo.MIMEApplicationJSON,
+		"*/*":                                 echo.MIMEApplicationJSON,
+		"application/xml":                     echo.MIMEApplicationXML,
+		"Application/XML":                     echo.MIMEApplicationXML,
+		"application/msgpack;q=0.8, text/xml": echo.MIMETextXML,
+		"application/xml;q=0, */*":            echo.MIMEApplicationJSON,
+		"text/html, application/*":            echo.MIMEApplicationJSON,
+		"image/png":                           echo.MIMEApplicationJSON,
+	}
+	for accept, want := range cases {
+		got, _ := negotiateCodec(accept)
+		assert.Equal(t, want, got, accept)
+	}
+}
+
+func TestXMLCodec_MarshalMap(t *testing.T) {
+	data, err := XMLCodec{}.Marshal(map[string]any{
+		"requestId": "r1",
+		"data":      map[string]any{"name": "a", "ids": []int64{1, 2}},
+		"details":   nil,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "<response><data><ids>1</ids><ids>2</ids><name>a</name></data><requestId>r1</requestId></response>", string(data))
+}
+
+func TestXMLCodec_MarshalNested(t *testing.T) {
+	data, err := XMLCodec{}.Marshal(map[string]any{
+		"data": map[string]string{"a": "b"},
+		"list": []map[string]any{{"id": 1, "tags": []string{"x", "y"}}, {"id": 2, "attrs": map[string]int{"n": 3}}},
+		"nil":  (*map[string]any)(nil),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "<response><data><a>b</a></data><list><id>1</id><tags>x</tags><tags>y</tags></list><list><attrs><n>3</n></attrs><id>2</id></list></response>", string(data))
+
+	// 顶层切片
+	data, err = XMLCodec{}.Marshal([]map[string]string{{"a": "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "<response><item><a>b</a></item></response>", string(data))
+
+	// 无法编码为 XML 的类型回退到 JSON
+	_, err = XMLCodec{}.Marshal(map[string]any{"m": map[int]string{1: "a"}})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+
+	for _, res := range []HttpResponse{
+		BaseHttpResponse{Data: map[string]string{"a": "b"}},
+		BaseHttpResponse{Data: []map[string]any{{"a": 1}}},
+		BaseHttpResponse{Data: map[string]any{"m": map[int]string{1: "a"}}},
+	} {
+		e := echo.New()
+		e.GET("/", func(c echo.Context) error {
+			return writeResponse(c, http.StatusOK, res.GetResponse("r1"))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "r1")
+	}
+}
+
+type testProtoMsg struct{ Name string }
+
+func (m *testProtoMsg) ProtoMessage()  {}
+func (m *testProtoMsg) Reset()         { *m = testProtoMsg{} }
+func (m *testProtoMsg) String() string { return m.Name }
+
+func TestProtobufCodec(t *testing.T) {
+	codec := ProtobufCodec{
+		MarshalFunc: func(m ProtoMessage) ([]byte, error) { return []byte(m.String()), nil },
+		UnmarshalFunc: func(data []byte, m ProtoMessage) error {
+			m.(*testProtoMsg).Name = string(data)
+			return nil
+		},
+	}
+
+	var msg testProtoMsg
+	assert.NoError(t, codec.Unmarshal([]byte("abc"), &msg))
+	assert.Equal(t, "abc", msg.Name)
+	data, err := codec.Marshal(&msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", string(data))
+
+	_, err = codec.Marshal(map[string]any{})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+}
+
+func TestBuildHandler_Codecs(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Create", http.MethodPost, "/create")
+
+	// MessagePack 请求和响应
+	body, err := MsgpackCodec{}.Marshal(map[string]any{"name": "a", "email": "a@b.cn"})
+	assert.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/create?page=1", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationMsgpack)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationMsgpack)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationMsgpack, rec.Header().Get(echo.HeaderContentType))
+
+	var res map[string]any
+	assert.NoError(t, MsgpackCodec{}.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, map[string]any{"name": "a", "email": "a@b.cn", "Page": int8(1)}, res["data"])
+
+	// XML 请求，JSON 响应
+	req = httptest.NewRequest(http.MethodPost, "/create?page=1", strings.NewReader("<req><Name>x</Name><Email>x@b.cn</Email></req>"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXMLCharsetUTF8)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"x"`)
+
+	// 错误响应同样按 Accept 编码
+	req = httptest.NewRequest(http.MethodPost, "/create?page=1", strings.NewReader("{}"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<code>VALIDATION_FAILED</code>")
+
+	// 错误详情与 JSON 使用相同的字段名
+	req = httptest.NewRequest(http.MethodPost, "/create?page=1", strings.NewReader(`{"email":"bad"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<details><field>name</field><rule>required</rule><message>不能为空</message></details>")
+	assert.Contains(t, rec.Body.String(), "<details><field>email</field><rule>email</rule><message>必须是有效的邮箱地址</message></details>")
+	req = httptest.NewRequest(http.MethodPost, "/create?page=1", strings.NewReader(`{"name":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), "<details><field>name</field><offset>9</offset><reason>类型错误: 期望 string, 实际为 number</reason></details>")
+
+	// 未注册的 Content-Type：严格模式返回 415
+	rec, res = doTestRequest(e, http.MethodPost, "/create?page=1", strings.NewReader("a=1"), "text/csv")
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", res["code"])
+
+	// 宽松模式按 JSON 尝试解码
+	lenient := newTestEcho(t, &testCtrl{}, "Create", http.MethodPost, "/create", func(r *Route) {
+		r.BindMode = BindModeLenient
+	})
+	rec, res = doTestRequest(lenient, http.MethodPost, "/create?page=1", strings.NewReader(`{"name":"a","email":"a@b.cn"}`), echo.MIMETextPlain)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a", res["data"].(map[string]any)["name"])
+	rec, res = doTestRequest(lenient, http.MethodPost, "/create?page=1", strings.NewReader("a=1"), "text/csv")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+}
@@ -43,6 +43,17 @@ func (ts *Date) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// 用于 query、param 等字符串参数的绑定，格式为 2006-01-02
+func (ts *Date) UnmarshalText(data []byte) error {
+	t, err := time.Parse(time.DateOnly, string(data))
+	if err != nil {
+		return err
+	}
+	*ts = Date(t)
+	return nil
+}
+
 func (ts Date) ToString() string {
 	return ts.ToTime().Format(time.DateOnly)
 }
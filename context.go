@@ -0,0 +1,76 @@
+package echoApi
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ctxKey 请求级数据在 context.Context 中的 key，使用未导出类型避免与其他包冲突
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	remoteIPKey
+	languageKey
+)
+
+// 旧版本在 context 中使用的字符串 key：保留一个版本，兼容 ctx.Value("requestId")、ctx.Value("remote_ip") 的读取，之后会移除
+// 新代码应使用 RequestID、RemoteIP
+const (
+	legacyRequestIDKey = "requestId"
+	legacyRemoteIPKey  = "remote_ip"
+)
+
+// echoContextKey echo.Context 中保存请求 context.Context 的 key（由 BaseErrorMiddleware 设置）
+const echoContextKey = "context"
+
+// Context 返回请求的 context.Context：携带 requestId 等请求级数据，客户端断开连接时取消
+// 未使用 BaseErrorMiddleware 时返回 c.Request().Context()
+func Context(c echo.Context) context.Context {
+	if ctx, ok := c.Get(echoContextKey).(context.Context); ok {
+		return ctx
+	}
+	return c.Request().Context()
+}
+
+// setContext 更新请求的 context.Context（同时替换 c.Request() 的 context）
+func setContext(c echo.Context, ctx context.Context) {
+	c.Set(echoContextKey, ctx)
+	c.SetRequest(c.Request().WithContext(ctx))
+}
+
+// WithRequestID 返回携带 requestId 的 context
+func WithRequestID(ctx context.Context, requestId string) context.Context {
+	ctx = context.WithValue(ctx, legacyRequestIDKey, requestId)
+	return context.WithValue(ctx, requestIDKey, requestId)
+}
+
+// RequestID 请求的 requestId，没有时返回空字符串
+func RequestID(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIDKey).(string)
+	return requestId
+}
+
+// WithRemoteIP 返回携带客户端 IP 的 context
+func WithRemoteIP(ctx context.Context, ip string) context.Context {
+	ctx = context.WithValue(ctx, legacyRemoteIPKey, ip)
+	return context.WithValue(ctx, remoteIPKey, ip)
+}
+
+// RemoteIP 客户端 IP，没有时返回空字符串
+func RemoteIP(ctx context.Context) string {
+	ip, _ := ctx.Value(remoteIPKey).(string)
+	return ip
+}
+
+// WithLanguage 返回携带请求语言的 context
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey, lang)
+}
+
+// Language 请求的语言（BaseErrorMiddleware 按 Accept-Language 从 SupportedLanguages 中协商），没有时返回空字符串
+func Language(ctx context.Context) string {
+	lang, _ := ctx.Value(languageKey).(string)
+	return lang
+}
@@ -0,0 +1,67 @@
+package echoApi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCtxCtrl struct{}
+
+func (t *testCtxCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{{Path: "/ctx", FuncName: "Info"}},
+	}
+}
+
+func (t *testCtxCtrl) Info(c echo.Context, ctx context.Context, req *testPageReq) map[string]any {
+	return map[string]any{
+		"requestId": RequestID(ctx) == RequestID(c.Request().Context()) && RequestID(ctx) != "",
+		"ip":        RemoteIP(ctx),
+		"legacy":    ctx.Value("requestId") == RequestID(ctx) && ctx.Value("remote_ip") == RemoteIP(ctx),
+		"lang":      Language(ctx),
+		"err":       ctx.Err() != nil,
+		"page":      req.Page,
+	}
+}
+
+func TestContextParam(t *testing.T) {
+	srv := NewServer("")
+	assert.NoError(t, srv.Register(&testCtxCtrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/ctx?page=2", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("Accept-Language", "fr, en-GB;q=0.8")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var res map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, map[string]any{"ip": "10.0.0.1", "legacy": true, "lang": "en", "err": false, "page": float64(2), "requestId": true}, res["data"])
+
+	// 客户端断开连接时 context 被取消
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ctx", nil).WithContext(cancelCtx))
+	assert.Contains(t, rec.Body.String(), `"err":true`)
+}
+
+func TestContextAccessors(t *testing.T) {
+	ctx := WithRemoteIP(WithRequestID(context.Background(), "r1"), "1.2.3.4")
+	assert.Equal(t, "r1", RequestID(ctx))
+	assert.Equal(t, "1.2.3.4", RemoteIP(ctx))
+	// 兼容旧版本的字符串 key
+	assert.Equal(t, "r1", ctx.Value("requestId"))
+	assert.Equal(t, "1.2.3.4", ctx.Value("remote_ip"))
+	assert.Equal(t, "", RequestID(context.Background()))
+
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+	assert.Equal(t, c.Request().Context(), Context(c))
+}
@@ -0,0 +1,60 @@
+package echoApi
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Cookie 响应需要设置的 cookie
+type Cookie struct {
+	Name     string
+	Value    string
+	Path     string
+	Domain   string
+	MaxAge   int           // 秒；0 表示会话 cookie，< 0 表示立即删除
+	SameSite http.SameSite // 0 表示不设置 SameSite 属性
+	Secure   bool
+	HttpOnly bool
+}
+
+// HTTPCookie 转换为 *http.Cookie
+func (ck Cookie) HTTPCookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     ck.Name,
+		Value:    ck.Value,
+		Path:     ck.Path,
+		Domain:   ck.Domain,
+		MaxAge:   ck.MaxAge,
+		SameSite: ck.SameSite,
+		Secure:   ck.Secure,
+		HttpOnly: ck.HttpOnly,
+	}
+}
+
+// ResponseCookies 响应（HttpResponse 或 HttpError）实现此接口时，EchoResponseAndRecoveryHandler 在写出响应前设置这些 cookie
+type ResponseCookies interface {
+	GetCookies() []Cookie
+}
+
+// setResponseCookies 设置响应声明的 cookie
+func setResponseCookies(c echo.Context, res any) {
+	rc, ok := res.(ResponseCookies)
+	if !ok {
+		return
+	}
+	for _, ck := range rc.GetCookies() {
+		c.SetCookie(ck.HTTPCookie())
+	}
+}
+
+// requestCookieValues 将请求中的 cookie 按名字分组（同名 cookie 可能出现多次）
+func requestCookieValues(r *http.Request) url.Values {
+	cookies := r.Cookies()
+	values := make(url.Values, len(cookies))
+	for _, ck := range cookies {
+		values[ck.Name] = append(values[ck.Name], ck.Value)
+	}
+	return values
+}
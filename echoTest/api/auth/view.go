@@ -109,16 +109,15 @@ func (a *Auth) CreateData(c echo.Context, query *CreateDataQuery, body *CreateDa
 	}
 }
 
-// UpdateUser 示例：PUT 请求，返回错误示例
-// func (a *Auth) UpdateUser(c echoApi.GContext, req UpdateUserReq) echoApi.HttpResponse {
+// UpdateUser 示例：PUT 请求，返回 (T, error)
+// 返回值 T 会包装为 BaseHttpResponse{Data: T}；返回 HttpError 类型的错误时按其状态码响应，不需要 panic
+// func (a *Auth) UpdateUser(c echo.Context, req UpdateUserReq) (map[string]any, error) {
 // 	if req.ID == "" {
-// 		panic(echoApi.BaseHttpError{
+// 		return nil, echoApi.BaseHttpError{
 // 			StatusCode: http.StatusBadRequest,
 // 			Code:       "INVALID_PARAM",
 // 			Message:    "ID 不能为空",
-// 		})
-// 	}
-// 	return echoApi.BaseHttpResponse{
-// 		Data: map[string]any{"updated": true},
+// 		}
 // 	}
+// 	return map[string]any{"updated": true}, nil
 // }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/coder/websocket"
 	"github.com/labstack/echo/v4"
+	"github.com/preceeder/echoApi"
 	"log/slog"
 	"time"
 )
@@ -14,9 +15,9 @@ import (
 // 路由: WS /api/ws
 // 功能：接收客户端消息并返回响应
 func (c *Chat) HandleWebSocket(gc echo.Context, conn *websocket.Conn) error {
-	ctx := gc.Get("context").(context.Context)
-	requestId := ctx.Value("requestId")
-	slog.Info("WebSocket 连接已建立", "requestId", requestId, "remoteAddr", ctx.Value("remote_ip"))
+	ctx := echoApi.Context(gc)
+	requestId := echoApi.RequestID(ctx)
+	slog.Info("WebSocket 连接已建立", "requestId", requestId, "remoteAddr", echoApi.RemoteIP(ctx))
 
 	baseCtx := gc.Request().Context()
 	if baseCtx == nil {
@@ -195,7 +196,7 @@ func (c *Chat) HandleWebSocket(gc echo.Context, conn *websocket.Conn) error {
 // 路由: WS /api/ws/echo
 // 功能：简单回显所有收到的消息
 func (c *Chat) HandleEcho(gc echo.Context, conn *websocket.Conn) error {
-	requestId := gc.Get("requestId")
+	requestId := echoApi.RequestID(echoApi.Context(gc))
 	slog.Info("Echo WebSocket 连接已建立", "requestId", requestId)
 
 	baseCtx := gc.Request().Context()
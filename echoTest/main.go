@@ -3,7 +3,6 @@ package main
 import (
 	_ "base-utils/echoTest/api/auth" // 导入控制器包，触发 init 注册
 	_ "base-utils/echoTest/api/chat" // 导入 WebSocket 控制器包，触发 init 注册
-	"context"
 	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/preceeder/echoApi"
@@ -31,7 +30,7 @@ func main() {
 			// 根据配置决定是否打印响应日志
 			if c.Get("PrintResponse") == "true" {
 				slog.InfoContext(
-					c.Get("context").(context.Context),
+					echoApi.Context(c),
 					"Method", req.Method,
 					"url", req.URL.String(),
 					"响应数据", "body", resp.Body.String(),
@@ -39,7 +38,7 @@ func main() {
 			} else if slices.Contains([]string{"POST", "PUT"}, req.Method) {
 				// POST 和 PUT 请求默认打印响应日志
 				slog.InfoContext(
-					c.Get("context").(context.Context),
+					echoApi.Context(c),
 					"Method", req.Method,
 					"url", req.URL.String(),
 					"响应数据", "body", logs.LogStr(resp.Body.String()),
@@ -0,0 +1,157 @@
+package echoApi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/labstack/echo/v4"
+)
+
+// KeyCase 信封 key 的命名风格
+type KeyCase int
+
+const (
+	KeyCaseCamel  KeyCase = iota // requestId（默认）
+	KeyCaseSnake                 // request_id
+	KeyCasePascal                // RequestId
+)
+
+// Envelope 响应信封格式，BaseHttpResponse、BaseHttpError 按此格式输出
+// key 为空时使用默认名称（requestId、code、message、data、meta、details），为 "-" 时不输出该成员；
+// KeyCase 对所有 key 生效
+//
+//	成功：{"requestId": "...", "code": SuccessCode, "message": "...", "data": ..., "meta": ...}
+//	失败：{"requestId": "...", "code": "...", "message": "...", "details": ...}
+type Envelope struct {
+	Bare           bool    // 成功响应直接输出 data，不包装（错误响应仍按信封输出）
+	KeyCase        KeyCase // key 的命名风格
+	RequestIdKey   string
+	CodeKey        string
+	MessageKey     string
+	DataKey        string
+	MetaKey        string
+	DetailsKey     string
+	SuccessCode    any    // 成功响应的 code，nil 时成功响应不输出 code
+	SuccessMessage string // 成功响应的 message（BaseHttpResponse.Message 为空时使用），为空时不输出
+
+	ErrorFormat     ErrorFormat // 错误响应格式，ErrorFormatProblem 时 HttpError 按 RFC 9457 problem+json 输出（路由可以单独配置）
+	ProblemTypeBase string      // problem+json 的 type 前缀，如 https://errors.example.com，type 为前缀 + 错误码；为空时 type 为 about:blank
+}
+
+// DefaultEnvelope 默认的响应信封（Server.Envelope 为 nil 以及直接调用 GetResponse 时使用）
+var DefaultEnvelope = &Envelope{}
+
+// envelopeKey echo.Context 中保存路由所属实例信封格式的 key
+const envelopeKey = "echoApi.envelope"
+
+// envelopeResponse 可以按信封格式输出的响应（BaseHttpResponse、BaseHttpError）
+type envelopeResponse interface {
+	envelopeBody(env *Envelope, requestId string) any
+}
+
+// responseBody 响应体：支持信封的响应按路由所属实例的信封格式输出，其他响应使用 GetResponse
+// 注册了错误码的 BaseHttpError 按请求的语言翻译；错误响应格式为 ErrorFormatProblem 时，HttpError 转换为 ProblemDetails
+func responseBody(c echo.Context, res interface{ GetResponse(string) any }, requestId string) any {
+	env := envelopeFor(c)
+	if herr, ok := res.(HttpError); ok {
+		herr = localizeError(c, herr)
+		if errorFormatFor(c, env) == ErrorFormatProblem {
+			return problemDetails(c, env, herr, requestId)
+		}
+		res = herr
+	}
+	if er, ok := res.(envelopeResponse); ok {
+		return er.envelopeBody(env, requestId)
+	}
+	return res.GetResponse(requestId)
+}
+
+// envelopeFor 请求使用的信封格式
+func envelopeFor(c echo.Context) *Envelope {
+	if env, ok := c.Get(envelopeKey).(*Envelope); ok {
+		return env
+	}
+	return DefaultEnvelope
+}
+
+// withEnvelope 路由的 handler 使用实例的信封格式（env 为 nil 时使用 DefaultEnvelope）
+func withEnvelope(env *Envelope, handler echo.HandlerFunc) echo.HandlerFunc {
+	if env == nil {
+		return handler
+	}
+	return func(c echo.Context) error {
+		c.Set(envelopeKey, env)
+		return handler(c)
+	}
+}
+
+// success 成功响应体
+func (env *Envelope) success(requestId string, data, meta any, message string) any {
+	if env.Bare {
+		return data
+	}
+	res := make(map[string]any, 5)
+	env.set(res, env.RequestIdKey, "requestId", requestId)
+	if env.SuccessCode != nil {
+		env.set(res, env.CodeKey, "code", env.SuccessCode)
+	}
+	if message == "" {
+		message = env.SuccessMessage
+	}
+	if message != "" {
+		env.set(res, env.MessageKey, "message", message)
+	}
+	env.set(res, env.DataKey, "data", data)
+	if meta != nil {
+		env.set(res, env.MetaKey, "meta", meta)
+	}
+	return res
+}
+
+// failure 错误响应体
+func (env *Envelope) failure(requestId, code, message string, details any) any {
+	res := make(map[string]any, 4)
+	env.set(res, env.RequestIdKey, "requestId", requestId)
+	env.set(res, env.CodeKey, "code", code)
+	env.set(res, env.MessageKey, "message", message)
+	if details != nil {
+		env.set(res, env.DetailsKey, "details", details)
+	}
+	return res
+}
+
+// set 按配置的 key 名称和命名风格写入成员
+func (env *Envelope) set(res map[string]any, key, defaultKey string, value any) {
+	switch key {
+	case "-":
+		return
+	case "":
+		key = defaultKey
+	}
+	res[env.KeyCase.apply(key)] = value
+}
+
+// apply 转换 key 的命名风格（key 按 camelCase 书写）
+func (k KeyCase) apply(key string) string {
+	switch k {
+	case KeyCaseSnake:
+		var b strings.Builder
+		for i, r := range key {
+			if unicode.IsUpper(r) {
+				if i > 0 {
+					b.WriteByte('_')
+				}
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	case KeyCasePascal:
+		if key == "" {
+			return key
+		}
+		return strings.ToUpper(key[:1]) + key[1:]
+	default:
+		return key
+	}
+}
@@ -0,0 +1,65 @@
+package echoApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseHttpResponse_GetResponse(t *testing.T) {
+	// 成功和错误响应的 requestId 命名一致
+	res := BaseHttpResponse{StatusCode: 201, Data: 1}.GetResponse("r1")
+	assert.Equal(t, map[string]any{"requestId": "r1", "data": 1}, res)
+
+	res = BaseHttpResponse{Data: 1, Meta: map[string]int{"total": 10}, Message: "ok"}.GetResponse("r1")
+	assert.Equal(t, map[string]any{"requestId": "r1", "data": 1, "message": "ok", "meta": map[string]int{"total": 10}}, res)
+
+	res = BaseHttpError{Code: "E", Message: "m"}.GetResponse("r1")
+	assert.Equal(t, map[string]any{"requestId": "r1", "code": "E", "message": "m"}, res)
+}
+
+func TestEnvelope(t *testing.T) {
+	env := &Envelope{KeyCase: KeyCaseSnake, RequestIdKey: "traceId", DataKey: "result", MetaKey: "-", SuccessCode: 0, SuccessMessage: "success"}
+	assert.Equal(t, map[string]any{"trace_id": "r1", "code": 0, "message": "success", "result": 1},
+		env.success("r1", 1, "meta", ""))
+	assert.Equal(t, map[string]any{"trace_id": "r1", "code": "E", "message": "m", "details": []int{1}},
+		env.failure("r1", "E", "m", []int{1}))
+
+	env = &Envelope{KeyCase: KeyCasePascal, Bare: true}
+	assert.Equal(t, 1, env.success("r1", 1, nil, ""))
+	assert.Equal(t, map[string]any{"RequestId": "r1", "Code": "E", "Message": "m"}, env.failure("r1", "E", "m", nil))
+}
+
+func TestServer_Envelope(t *testing.T) {
+	srv := NewServer("/api")
+	srv.Envelope = &Envelope{Bare: true, KeyCase: KeyCaseSnake}
+	assert.NoError(t, srv.Register(&testResultCtrl{}))
+	assert.NoError(t, srv.Register(&testUserV2Ctrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	rec, _ := doTestRequest(e, http.MethodGet, "/api/pair?page=2", nil, "")
+	assert.JSONEq(t, `{"name":"p","page":2}`, rec.Body.String())
+
+	// 错误响应仍按信封输出
+	rec, res := doTestRequest(e, http.MethodGet, "/api/pair", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "NOT_FOUND", res["code"])
+	assert.NotEmpty(t, res["request_id"])
+
+	// 版本分发的错误同样使用实例的信封格式
+	req := httptest.NewRequest(http.MethodGet, "/api/user/info", nil)
+	req.Header.Set("x-auth-version", "1")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"request_id"`)
+
+	// 其他实例不受影响
+	other := NewServer("/api")
+	assert.NoError(t, other.Register(&testResultCtrl{}))
+	_, res = doTestRequest(other.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil)), http.MethodGet, "/api/pair?page=2", nil, "")
+	assert.NotEmpty(t, res["requestId"])
+	assert.NotNil(t, res["data"])
+}
@@ -0,0 +1,228 @@
+package echoApi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrorCode 注册的错误码：HTTP 状态码和各语言的消息模板
+// 消息模板使用 fmt 格式，不同语言可以用 %[2]s 调整参数顺序
+type ErrorCode struct {
+	Status   int               `json:"status" yaml:"status"`
+	Messages map[string]string `json:"messages" yaml:"messages"` // 语言（如 zh、en、zh-tw）-> 消息模板
+}
+
+// DefaultLanguage 请求未指定语言或没有对应翻译时使用的语言
+var DefaultLanguage = "zh"
+
+// errorCodes 错误码注册表（使用读写锁保护），内置框架自身的错误码
+var (
+	errorCodes = map[string]ErrorCode{
+		"INTERNAL_ERROR":         {Status: 500, Messages: map[string]string{"zh": "内部服务器错误", "en": "internal server error"}},
+		"INVALID_BODY":           {Status: 400, Messages: map[string]string{"zh": "读取请求体失败: %s", "en": "failed to read request body: %s"}},
+		"INVALID_FORM":           {Status: 400, Messages: map[string]string{"zh": "表单解析失败: %s", "en": "invalid form: %s"}},
+		"INVALID_PARAM":          {Status: 400, Messages: map[string]string{"zh": "参数绑定失败: %s", "en": "invalid parameter: %s"}},
+		"VALIDATION_FAILED":      {Status: 400, Messages: map[string]string{"zh": "参数校验失败", "en": "validation failed"}},
+		"BODY_TOO_LARGE":         {Status: 413, Messages: map[string]string{"zh": "请求体超出大小限制 %d 字节", "en": "request body exceeds the limit of %d bytes"}},
+		"UNSUPPORTED_MEDIA_TYPE": {Status: 415, Messages: map[string]string{"zh": "不支持的 Content-Type: %s", "en": "unsupported Content-Type: %s"}},
+		"UNSUPPORTED_VERSION":    {Status: 400, Messages: map[string]string{"zh": "不支持的 API 版本: %s", "en": "unsupported API version: %s"}},
+		"UNAUTHORIZED":           {Status: 401, Messages: map[string]string{"zh": "未授权", "en": "unauthorized"}},
+
+		// 请求体绑定错误的消息（BindError）
+		"BIND_BODY":          {Messages: map[string]string{"zh": "请求体格式错误: %s", "en": "invalid request body: %s"}},
+		"BIND_OFFSET":        {Messages: map[string]string{"zh": "请求体格式错误(offset %d): %s", "en": "invalid request body (offset %d): %s"}},
+		"BIND_FIELD":         {Messages: map[string]string{"zh": "字段 %s 绑定失败: %s", "en": "field %s: %s"}},
+		"BIND_FIELD_OFFSET":  {Messages: map[string]string{"zh": "字段 %s 绑定失败(offset %d): %s", "en": "field %s (offset %d): %s"}},
+		"BIND_UNKNOWN_FIELD": {Messages: map[string]string{"zh": "未知字段", "en": "unknown field"}},
+		"BIND_TYPE_MISMATCH": {Messages: map[string]string{"zh": "类型错误: 期望 %s, 实际为 %s", "en": "type mismatch: expected %s, got %s"}},
+
+		// 字段校验规则的消息（FieldError.Message）
+		"VALIDATION_REQUIRED":   {Messages: map[string]string{"zh": "不能为空", "en": "is required"}},
+		"VALIDATION_MIN":        {Messages: map[string]string{"zh": "不能小于 %s", "en": "must be at least %s"}},
+		"VALIDATION_MIN_LENGTH": {Messages: map[string]string{"zh": "长度不能小于 %s", "en": "length must be at least %s"}},
+		"VALIDATION_MAX":        {Messages: map[string]string{"zh": "不能大于 %s", "en": "must be at most %s"}},
+		"VALIDATION_MAX_LENGTH": {Messages: map[string]string{"zh": "长度不能大于 %s", "en": "length must be at most %s"}},
+		"VALIDATION_LEN":        {Messages: map[string]string{"zh": "长度必须等于 %s", "en": "length must be %s"}},
+		"VALIDATION_ONEOF":      {Messages: map[string]string{"zh": "必须是 [%s] 中的一个", "en": "must be one of [%s]"}},
+		"VALIDATION_REGEX":      {Messages: map[string]string{"zh": "格式不正确", "en": "has an invalid format"}},
+		"VALIDATION_EMAIL":      {Messages: map[string]string{"zh": "必须是有效的邮箱地址", "en": "must be a valid email address"}},
+		"VALIDATION_URL":        {Messages: map[string]string{"zh": "必须是有效的 URL", "en": "must be a valid URL"}},
+	}
+	errorCodesMu sync.RWMutex
+)
+
+// RegisterErrorCode 注册错误码，已存在时合并：Status 为 0 时保留原状态码，消息按语言覆盖
+// 可以用来为内置错误码（如 VALIDATION_FAILED）增加其他语言的翻译
+func RegisterErrorCode(code string, ec ErrorCode) {
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+
+	merged := errorCodes[code]
+	if ec.Status != 0 {
+		merged.Status = ec.Status
+	}
+	messages := make(map[string]string, len(merged.Messages)+len(ec.Messages))
+	for lang, msg := range merged.Messages {
+		messages[lang] = msg
+	}
+	for lang, msg := range ec.Messages {
+		messages[strings.ToLower(lang)] = msg
+	}
+	merged.Messages = messages
+	errorCodes[code] = merged
+}
+
+// GetErrorCode 获取注册的错误码
+func GetErrorCode(code string) (ErrorCode, bool) {
+	errorCodesMu.RLock()
+	defer errorCodesMu.RUnlock()
+	ec, ok := errorCodes[code]
+	return ec, ok
+}
+
+// LoadErrorCatalog 从 JSON 或 YAML 文件（按扩展名 .json、.yaml、.yml）加载错误码：
+//
+//	ORDER_NOT_FOUND:
+//	  status: 404
+//	  messages:
+//	    zh: 订单 %s 不存在
+//	    en: order %s not found
+func LoadErrorCatalog(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return ParseErrorCatalog(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// ParseErrorCatalog 解析 JSON 或 YAML（format 为 json、yaml 或 yml）格式的错误码并注册
+func ParseErrorCatalog(data []byte, format string) error {
+	var catalog map[string]ErrorCode
+	var err error
+	switch strings.ToLower(format) {
+	case "json":
+		err = json.Unmarshal(data, &catalog)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &catalog)
+	default:
+		return fmt.Errorf("不支持的错误码文件格式: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("解析错误码文件失败: %w", err)
+	}
+	for code, ec := range catalog {
+		RegisterErrorCode(code, ec)
+	}
+	return nil
+}
+
+// NewError 按注册的错误码创建 HttpError，args 为消息模板的参数
+// Message 使用 DefaultLanguage 的消息，响应时按请求的 Accept-Language 重新翻译；未注册的错误码状态码为 500
+func NewError(code string, args ...any) BaseHttpError {
+	ec, ok := GetErrorCode(code)
+	status := ec.Status
+	if !ok || status == 0 {
+		status = 500
+	}
+	message, ok := ec.message([]string{DefaultLanguage}, args)
+	if !ok {
+		message = code
+	}
+	return BaseHttpError{StatusCode: status, Code: code, Message: message, Args: args}
+}
+
+// errorMessage 错误码在 DefaultLanguage 下的消息
+func errorMessage(code string, args ...any) string {
+	message, _ := translate(code, []string{DefaultLanguage}, args...)
+	return message
+}
+
+// translate 按语言优先级翻译错误码的消息
+func translate(code string, langs []string, args ...any) (string, bool) {
+	ec, ok := GetErrorCode(code)
+	if !ok {
+		return "", false
+	}
+	return ec.message(langs, args)
+}
+
+// message 按语言优先级选择消息模板（先精确匹配，再匹配主语言，如 zh-cn -> zh）
+func (ec ErrorCode) message(langs []string, args []any) (string, bool) {
+	for _, lang := range langs {
+		lang = strings.ToLower(lang)
+		tmpl, ok := ec.Messages[lang]
+		if !ok {
+			base, _, found := strings.Cut(lang, "-")
+			if !found {
+				continue
+			}
+			if tmpl, ok = ec.Messages[base]; !ok {
+				continue
+			}
+		}
+		if len(args) == 0 {
+			return tmpl, true
+		}
+		return fmt.Sprintf(tmpl, args...), true
+	}
+	return "", false
+}
+
+// requestLanguages 翻译消息使用的语言：请求的语言（见 Language），其次是 DefaultLanguage
+// 未使用 BaseErrorMiddleware 时按 Accept-Language 协商
+func requestLanguages(c echo.Context) []string {
+	lang := Language(Context(c))
+	if lang == "" {
+		lang = NegotiateLanguage(c.Request().Header.Get("Accept-Language"), SupportedLanguages, DefaultLanguage)
+	}
+	return []string{lang, DefaultLanguage}
+}
+
+// localizeError 按请求的语言翻译注册了错误码的 BaseHttpError（包括请求体绑定和字段校验错误），其他错误原样返回
+func localizeError(c echo.Context, herr HttpError) HttpError {
+	var base BaseHttpError
+	switch v := herr.(type) {
+	case BaseHttpError:
+		base = v
+	case *BaseHttpError:
+		base = *v
+	default:
+		return herr
+	}
+
+	langs := requestLanguages(c)
+	switch details := base.Details.(type) {
+	case *BindError:
+		base.Message = details.message(langs)
+		base.Details = details.localize(langs)
+		return base
+	case ValidationErrors:
+		base.Details = details.localize(langs)
+	}
+	// 只翻译 NewError 生成的消息，自定义的 Message 原样返回
+	if base.Message == errorMessage(base.Code, base.Args...) {
+		if message, ok := translate(base.Code, langs, base.Args...); ok {
+			base.Message = message
+		}
+	}
+	return base
+}
+
+// localize 翻译字段校验错误的消息
+func (v ValidationErrors) localize(langs []string) ValidationErrors {
+	res := make(ValidationErrors, len(v))
+	for i, fe := range v {
+		if message, ok := translate(fe.code, langs, fe.args...); ok {
+			fe.Message = message
+		}
+		res[i] = fe
+	}
+	return res
+}
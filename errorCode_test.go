@@ -0,0 +1,111 @@
+package echoApi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOrderIdReq struct {
+	Id string `param:"id"`
+}
+
+func testGetOrder(c echo.Context, req *testOrderIdReq) (map[string]any, error) {
+	return nil, NewError("TEST_ORDER_NOT_FOUND", req.Id)
+}
+
+func TestLoadErrorCatalog(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "errors.yaml")
+	assert.NoError(t, os.WriteFile(yamlPath, []byte(`
+TEST_ORDER_NOT_FOUND:
+  status: 404
+  messages:
+    zh: 订单 %s 不存在
+    en: order %s not found
+`), 0o644))
+	assert.NoError(t, LoadErrorCatalog(yamlPath))
+
+	jsonPath := filepath.Join(dir, "errors.json")
+	assert.NoError(t, os.WriteFile(jsonPath, []byte(`{"TEST_ORDER_NOT_FOUND": {"messages": {"ZH-TW": "訂單 %s 不存在"}}}`), 0o644))
+	assert.NoError(t, LoadErrorCatalog(jsonPath))
+
+	ec, ok := GetErrorCode("TEST_ORDER_NOT_FOUND")
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, ec.Status)
+	assert.Equal(t, "訂單 %s 不存在", ec.Messages["zh-tw"])
+	assert.Equal(t, "order %s not found", ec.Messages["en"])
+
+	assert.Error(t, ParseErrorCatalog([]byte("{}"), "toml"))
+	assert.Error(t, ParseErrorCatalog([]byte("{"), "json"))
+
+	herr := NewError("TEST_ORDER_NOT_FOUND", "42")
+	assert.Equal(t, http.StatusNotFound, herr.StatusCode)
+	assert.Equal(t, "订单 42 不存在", herr.Message)
+	assert.Equal(t, http.StatusInternalServerError, NewError("TEST_UNKNOWN").StatusCode)
+}
+
+func TestLocalizeError(t *testing.T) {
+	RegisterErrorCode("TEST_ORDER_NOT_FOUND", ErrorCode{Status: http.StatusNotFound, Messages: map[string]string{
+		"zh": "订单 %s 不存在",
+		"en": "order %s not found",
+	}})
+	srv := NewServer("/api")
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/order/:id"}, testGetOrder))
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/create"}, testTypedCreate))
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/strict", DisallowUnknown: true}, testTypedCreate))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	request := func(method, target, body, lang string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Accept-Language", lang)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var res map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &res)
+		return rec, res
+	}
+
+	// 业务错误码
+	rec, res := request(http.MethodGet, "/api/order/42", "", "en-US,zh;q=0.5")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "TEST_ORDER_NOT_FOUND", res["code"])
+	assert.Equal(t, "order 42 not found", res["message"])
+	_, res = request(http.MethodGet, "/api/order/42", "", "fr")
+	assert.Equal(t, "订单 42 不存在", res["message"])
+
+	// 框架的校验错误，包括字段消息
+	_, res = request(http.MethodPost, "/api/create?page=0", `{"email":"bad"}`, "en")
+	assert.Equal(t, "validation failed", res["message"])
+	details, _ := res["details"].([]any)
+	var messages []any
+	for _, d := range details {
+		messages = append(messages, d.(map[string]any)["message"])
+	}
+	assert.ElementsMatch(t, []any{"is required", "must be a valid email address", "must be at least 1"}, messages)
+
+	// 请求体绑定错误
+	_, res = request(http.MethodPost, "/api/create?page=1", `{"name":1}`, "en")
+	assert.Equal(t, "INVALID_BODY", res["code"])
+	assert.Equal(t, "field name (offset 9): type mismatch: expected string, got number", res["message"])
+	assert.Equal(t, map[string]any{"field": "name", "offset": float64(9), "reason": "type mismatch: expected string, got number"}, res["details"])
+	_, res = request(http.MethodPost, "/api/create?page=1", `{"name":1}`, "")
+	assert.Equal(t, "字段 name 绑定失败(offset 9): 类型错误: 期望 string, 实际为 number", res["message"])
+	assert.Equal(t, "类型错误: 期望 string, 实际为 number", res["details"].(map[string]any)["reason"])
+
+	_, res = request(http.MethodPost, "/api/strict?page=1", `{"name":"a","email":"a@b.cn","x":1}`, "en")
+	assert.Equal(t, "field x: unknown field", res["message"])
+	assert.Equal(t, "unknown field", res["details"].(map[string]any)["reason"])
+
+	// 自定义消息不翻译
+	_, res = request(http.MethodPost, "/api/create?page=1", `{"name":"forbidden","email":"a@b.cn"}`, "en")
+	assert.Equal(t, "forbidden", res["message"])
+}
@@ -0,0 +1,90 @@
+package echoApi
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultMaxMultipartMemory multipart 解析时保存在内存中的最大字节数，超出部分写入临时文件
+// 路由可以通过 RouteBuilder.MaxMultipartMemory 单独配置
+var DefaultMaxMultipartMemory int64 = 32 << 20
+
+var (
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeaderSliceType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// requestForm 解析后的表单数据（application/x-www-form-urlencoded 或 multipart/form-data）
+type requestForm struct {
+	Values url.Values
+	Files  map[string][]*multipart.FileHeader
+}
+
+// has 表单中是否有 name 的非空值或文件，form 为 nil（非表单请求）时返回 false
+func (f *requestForm) has(name string) bool {
+	if f == nil {
+		return false
+	}
+	return len(f.Files[name]) > 0 || len(nonEmptyValues(f.Values[name])) > 0
+}
+
+// isFormRequest 判断请求体是否为表单
+func isFormRequest(r *http.Request) bool {
+	ct := r.Header.Get(echo.HeaderContentType)
+	return strings.HasPrefix(ct, echo.MIMEApplicationForm) || strings.HasPrefix(ct, echo.MIMEMultipartForm)
+}
+
+// parseRequestForm 解析表单，非表单请求返回 nil
+// maxMemory 只对 multipart 生效，<= 0 时使用 DefaultMaxMultipartMemory
+func parseRequestForm(r *http.Request, maxMemory int64) (*requestForm, error) {
+	ct := r.Header.Get(echo.HeaderContentType)
+	switch {
+	case strings.HasPrefix(ct, echo.MIMEMultipartForm):
+		if maxMemory <= 0 {
+			maxMemory = DefaultMaxMultipartMemory
+		}
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return nil, err
+		}
+		return &requestForm{Values: r.PostForm, Files: r.MultipartForm.File}, nil
+	case strings.HasPrefix(ct, echo.MIMEApplicationForm):
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return &requestForm{Values: r.PostForm}, nil
+	}
+	return nil, nil
+}
+
+// setFieldValueFromForm 从表单绑定字段，返回是否找到对应的值
+// 支持 *multipart.FileHeader 和 []*multipart.FileHeader 类型的上传文件字段
+func setFieldValueFromForm(fieldValue reflect.Value, fieldType reflect.Type, form *requestForm, name string, split bool, format string) (bool, error) {
+	switch fieldType {
+	case fileHeaderType:
+		if files := form.Files[name]; len(files) > 0 {
+			fieldValue.Set(reflect.ValueOf(files[0]))
+			return true, nil
+		}
+		return false, nil
+	case fileHeaderSliceType:
+		if files := form.Files[name]; len(files) > 0 {
+			fieldValue.Set(reflect.ValueOf(files))
+			return true, nil
+		}
+		return false, nil
+	}
+
+	values := nonEmptyValues(form.Values[name])
+	if len(values) == 0 {
+		return false, nil
+	}
+	if split {
+		values = splitValues(values)
+	}
+	return true, setFieldValueFromStrings(fieldValue, fieldType, values, format)
+}
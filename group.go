@@ -0,0 +1,100 @@
+package echoApi
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Group 路由分组，每一级提供路径前缀、中间件和 CtxParams，可以嵌套，如 /api/v1/admin
+// 同一个控制器可以注册到多个分组下，无需复制 RouteConfig
+//
+//	v1 := srv.Group("/v1", authMiddleware)
+//	admin := v1.Group("/admin", adminMiddleware).WithCtxParams(map[string]string{"role": "admin"})
+//	_ = admin.Register(&UserController{}) // /api/v1/admin/user/...
+//	_ = v1.Register(&UserController{})    // /api/v1/user/...
+type Group struct {
+	server      *Server
+	parent      *Group
+	prefix      string
+	middlewares []echo.MiddlewareFunc
+	ctxParams   map[string]string
+}
+
+// NewGroup 在默认实例上创建顶层分组
+func NewGroup(prefix string, middlewares ...echo.MiddlewareFunc) *Group {
+	return defaultServer.Group(prefix, middlewares...)
+}
+
+// Group 创建顶层分组
+func (s *Server) Group(prefix string, middlewares ...echo.MiddlewareFunc) *Group {
+	return &Group{server: s, prefix: prefix, middlewares: middlewares}
+}
+
+// Group 创建子分组，路径前缀、中间件和 CtxParams 在父分组之后叠加
+func (g *Group) Group(prefix string, middlewares ...echo.MiddlewareFunc) *Group {
+	return &Group{server: g.server, parent: g, prefix: prefix, middlewares: middlewares}
+}
+
+// Use 添加分组中间件（只对之后注册的控制器生效）
+func (g *Group) Use(middlewares ...echo.MiddlewareFunc) *Group {
+	g.middlewares = append(g.middlewares, middlewares...)
+	return g
+}
+
+// WithCtxParams 设置分组写入 ctx 的数据，同名 key 子分组覆盖父分组，路由自己的配置覆盖分组
+func (g *Group) WithCtxParams(params map[string]string) *Group {
+	if g.ctxParams == nil {
+		g.ctxParams = make(map[string]string, len(params))
+	}
+	for k, v := range params {
+		g.ctxParams[k] = v
+	}
+	return g
+}
+
+// Register 注册控制器到分组
+func (g *Group) Register(ctrl Controller) error {
+	return g.server.register(ctrl, g)
+}
+
+// chain 从最外层到当前分组
+func (g *Group) chain() []*Group {
+	var groups []*Group
+	for cur := g; cur != nil; cur = cur.parent {
+		groups = append([]*Group{cur}, groups...)
+	}
+	return groups
+}
+
+// apply 将分组链的前缀、中间件和 CtxParams 应用到路由（g 为 nil 时不做处理）
+// 中间件按外层分组、内层分组、路由自身的顺序执行
+func (g *Group) apply(route *Route) {
+	if g == nil {
+		return
+	}
+
+	var prefix strings.Builder
+	var middlewares []echo.MiddlewareFunc
+	ctxParams := make(map[string]string)
+	for _, cur := range g.chain() {
+		if p := strings.Trim(cur.prefix, "/"); p != "" {
+			prefix.WriteString("/" + p)
+		}
+		middlewares = append(middlewares, cur.middlewares...)
+		for k, v := range cur.ctxParams {
+			ctxParams[k] = v
+		}
+	}
+
+	if prefix.Len() > 0 {
+		route.Path = buildPath(prefix.String(), route.Path)
+	}
+	route.Middlewares = append(middlewares, route.Middlewares...)
+	for k, v := range route.CtxParams {
+		ctxParams[k] = v
+	}
+	if len(ctxParams) > 0 {
+		route.CtxParams = ctxParams
+	}
+}
@@ -0,0 +1,79 @@
+package echoApi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testGroupCtrl struct{}
+
+func (t *testGroupCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/info", FuncName: "Info", CtxParams: map[string]string{"scope": "route"}, Middlewares: []echo.MiddlewareFunc{testTraceMiddleware("route")}},
+			{Path: "/", FuncName: "Info"},
+		},
+	}
+}
+
+func (t *testGroupCtrl) Info(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: map[string]any{
+		"path":  c.Path(),
+		"role":  c.Get("role"),
+		"scope": c.Get("scope"),
+		"trace": c.Get("trace"),
+	}}
+}
+
+// testTraceMiddleware 记录中间件执行顺序
+func testTraceMiddleware(name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			trace, _ := c.Get("trace").(string)
+			c.Set("trace", trace+"/"+name)
+			return next(c)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	srv := NewServer("/api")
+	v1 := srv.Group("/v1", testTraceMiddleware("v1")).WithCtxParams(map[string]string{"role": "user", "scope": "v1"})
+	admin := v1.Group("admin/", testTraceMiddleware("admin")).WithCtxParams(map[string]string{"role": "admin"})
+
+	// 同一个控制器挂载到多个分组
+	assert.NoError(t, admin.Register(&testGroupCtrl{}))
+	assert.NoError(t, v1.Register(&testGroupCtrl{}))
+	assert.NoError(t, srv.Register(&testGroupCtrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	_, res := doTestRequest(e, http.MethodGet, "/api/v1/admin/info", nil, "")
+	assert.Equal(t, map[string]any{
+		"path":  "/api/v1/admin/info",
+		"role":  "admin",
+		"scope": "route",
+		"trace": "/v1/admin/route",
+	}, res["data"])
+
+	_, res = doTestRequest(e, http.MethodGet, "/api/v1/info", nil, "")
+	assert.Equal(t, map[string]any{
+		"path":  "/api/v1/info",
+		"role":  "user",
+		"scope": "route",
+		"trace": "/v1/route",
+	}, res["data"])
+
+	_, res = doTestRequest(e, http.MethodGet, "/api/v1", nil, "")
+	assert.Equal(t, "v1", res["data"].(map[string]any)["scope"])
+
+	_, res = doTestRequest(e, http.MethodGet, "/api/info", nil, "")
+	assert.Equal(t, map[string]any{
+		"path":  "/api/info",
+		"role":  nil,
+		"scope": "route",
+		"trace": "/route",
+	}, res["data"])
+}
@@ -0,0 +1,136 @@
+package echoApi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Router 可以注册泛型 handler 的路由表：*Server 或 *Group
+type Router interface {
+	routeTarget() (*Server, *Group)
+}
+
+func (s *Server) routeTarget() (*Server, *Group) {
+	return s, nil
+}
+
+func (g *Group) routeTarget() (*Server, *Group) {
+	return g.server, g
+}
+
+// Handle 注册泛型 handler，请求直接绑定到 *Req，返回值交给响应中间件处理
+// 签名在编译期检查，调用 handler 不经过反射；与 Controller 方式共用绑定、默认值、校验和响应流程
+// builder 的 Method / Methods 指定 HTTP 方法，其余配置与 RouteBuilder 相同（FuncName、UseModel 不使用）：
+//
+//	echoApi.Handle(srv, echoApi.RouteBuilder{Method: http.MethodPost, Path: "/order"},
+//		func(c echo.Context, req *CreateOrderReq) (*Order, error) { ... })
+//
+// handler 返回 HttpError 类型的错误时按错误响应输出，其他错误交给 EchoResponseAndRecoveryHandler（500）
+func Handle[Req, Resp any](r Router, builder RouteBuilder, handler func(c echo.Context, req *Req) (Resp, error)) error {
+	server, group := r.routeTarget()
+	name := funcName(handler)
+	builder.FuncName = name
+	builder.UseModel = false
+
+	reqType := reflect.TypeOf((*Req)(nil))
+	binding, err := newParamBinding(reqType)
+	if err == nil && reqType.Elem().Kind() != reflect.Struct {
+		err = fmt.Errorf("%w: 请求参数 %s 必须是结构体", ErrInvalidSignature, reqType.Elem())
+	}
+
+	// 复用自定义方法路由的展开逻辑（方法名大写、Methods 展开、路径规范化）
+	pathMap, errs := expandRouteConfig(RouteConfig{Custom: []RouteBuilder{builder}}, "")
+	var newRoutes []Route
+	for _, b := range pathMap[name] {
+		routeErr := err
+		if routeErr == nil && b.Method == "WS" {
+			routeErr = fmt.Errorf("%w: WebSocket 路由不支持泛型 handler", ErrInvalidSignature)
+		}
+		if routeErr == nil {
+			routeErr = checkBuilderVersion(b)
+		}
+		if routeErr != nil {
+			errs = append(errs, &RouteError{FuncName: name, Method: b.Method, Path: b.Path, Err: routeErr})
+			continue
+		}
+
+		route := newRoute(b)
+		route.Params = []ParamBinding{binding}
+		route.FuncName = name
+		route.build = typedHandlerBuilder(handler)
+		newRoutes = append(newRoutes, route)
+	}
+	return server.addRoutes(newRoutes, group, errs)
+}
+
+// typedHandlerBuilder 泛型 handler 的核心处理器
+func typedHandlerBuilder[Req, Resp any](handler func(c echo.Context, req *Req) (Resp, error)) func(route Route) echo.HandlerFunc {
+	return func(route Route) echo.HandlerFunc {
+		binder := newRequestBinder(route)
+		paramBind := &binder.params[0]
+
+		return func(c echo.Context) error {
+			state, herr := binder.begin(c)
+			if herr != nil {
+				return writeBindError(c, herr)
+			}
+
+			req := new(Req)
+			if herr := binder.bind(c, state, paramBind, reflect.ValueOf(req)); herr != nil {
+				return writeBindError(c, herr)
+			}
+			if herr := state.validationError(); herr != nil {
+				return writeBindError(c, herr)
+			}
+
+			resp, err := handler(c, req)
+			return setHandlerResult(c, resp, err)
+		}
+	}
+}
+
+// setHandlerResult 将 handler 的返回值交给响应中间件（c.Set("Response")）
+// HttpError 类型的错误按其状态码作为错误响应，其他错误直接返回（EchoResponseAndRecoveryHandler 按 500 处理）
+// 返回值实现了 HttpResponse / HttpError 时原样使用，其他值（包括 nil 切片、nil 指针等有类型的 nil）包装为 BaseHttpResponse{Data: v}
+// 只有无类型的 nil（如返回 nil 的 HttpResponse）表示 handler 自己写出了响应
+func setHandlerResult(c echo.Context, resp any, err error) error {
+	if err != nil && !isNilResult(err) {
+		var htperr HttpError
+		if errors.As(err, &htperr) {
+			c.Set("Response", htperr)
+			return nil
+		}
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	switch resp.(type) {
+	case HttpResponse, HttpError:
+		if isNilResult(resp) {
+			// 有类型的 nil 无法调用 GetResponse，按空数据响应
+			c.Set("Response", BaseHttpResponse{})
+			return nil
+		}
+		c.Set("Response", resp)
+	default:
+		c.Set("Response", BaseHttpResponse{Data: resp})
+	}
+	return nil
+}
+
+// isNilResult 返回值是否为 nil（包括 nil 指针、nil map 等）
+func isNilResult(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
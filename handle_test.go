@@ -0,0 +1,79 @@
+package echoApi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTypedResp struct {
+	Name string `json:"name"`
+	Page int    `json:"page"`
+}
+
+func testTypedCreate(c echo.Context, req *testCreateReq) (*testTypedResp, error) {
+	switch req.Name {
+	case "forbidden":
+		return nil, BaseHttpError{StatusCode: http.StatusForbidden, Code: "FORBIDDEN", Message: "forbidden"}
+	case "fail":
+		return nil, errors.New("fail")
+	case "empty":
+		return nil, nil
+	}
+	return &testTypedResp{Name: req.Name, Page: req.Page}, nil
+}
+
+func TestHandle(t *testing.T) {
+	srv := NewServer("/api")
+	assert.NoError(t, Handle(srv.Group("/typed"), RouteBuilder{Methods: []string{http.MethodPost, http.MethodPut}, Path: "/create", Name: "typed.create"}, testTypedCreate))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	// 与 Controller 方式共用绑定、默认值和校验
+	rec, res := doTestRequest(e, http.MethodPut, "/api/typed/create?page=2", strings.NewReader(`{"name":"a","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{"name": "a", "page": float64(2)}, res["data"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/api/typed/create?page=0", strings.NewReader(`{"email":"bad"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/api/typed/create?page=1", strings.NewReader(`{"name":"forbidden","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "FORBIDDEN", res["code"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/api/typed/create?page=1", strings.NewReader(`{"name":"fail","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "INTERNAL_ERROR", res["code"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/api/typed/create?page=1", strings.NewReader(`{"name":"empty","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, res["data"])
+	assert.Contains(t, res, "data")
+
+	url, err := srv.URLFor("typed.create")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/typed/create", url)
+	assert.Equal(t, "echoApi.testTypedCreate", srv.Routes()[0].Handler)
+	assert.Equal(t, []string{"*echoApi.testCreateReq"}, srv.Routes()[0].Params)
+}
+
+func TestHandle_Invalid(t *testing.T) {
+	srv := NewServer("/api")
+	err := Handle(srv, RouteBuilder{Path: "/none"}, testTypedCreate)
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+
+	err = Handle(srv, RouteBuilder{Method: "WS", Path: "/ws"}, testTypedCreate)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	err = Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/int"}, func(c echo.Context, req *int) (any, error) { return nil, nil })
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/dup"}, testTypedCreate))
+	err = Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/dup"}, testTypedCreate)
+	assert.ErrorIs(t, err, ErrDuplicateRoute)
+	assert.Empty(t, srv.Routes()[1:])
+}
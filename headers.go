@@ -1,8 +1,6 @@
 package echoApi
 
 import (
-	"slices"
-	"strconv"
 	"strings"
 )
 
@@ -25,48 +23,97 @@ type LangQ struct {
 }
 
 // ParseAcceptLanguage
-// Accept-Language 解析
+// Accept-Language 解析，按 q 值从高到低排序（q 相同时保持原顺序），语言标签保持原始写法（比较时应忽略大小写）
+// 格式错误的 q 值按 1 处理，q=0 的项保留（表示不接受该语言）
 func ParseAcceptLanguage(acptLang string) []LangQ {
 	var lqs []LangQ
+	for _, item := range parseQualityList(acptLang) {
+		lqs = append(lqs, LangQ{Lang: item.Value, Q: item.Quality})
+	}
+	return lqs
+}
+
+// GetAcceptLanguage 获取语言  值返回排名第一的值（不含 * 和 q=0 的项），没有时返回 en
+func GetAcceptLanguage(acptlang string) string {
+	for _, lq := range ParseAcceptLanguage(acptlang) {
+		if lq.Q > 0 && lq.Lang != "*" {
+			return lq.Lang
+		}
+	}
+	return "en"
+}
 
-	langQStrs := strings.Split(acptLang, ",")
-	for _, langQStr := range langQStrs {
-		trimedLangQStr := strings.Trim(langQStr, " ")
+// SupportedLanguages 支持的语言，BaseErrorMiddleware 按 Accept-Language 从中协商请求的语言（见 Language）
+// 默认为内置错误码消息的语言，增加翻译时一并添加
+var SupportedLanguages = []string{"zh", "en"}
+
+// NegotiateLanguage 按 Accept-Language 从 supported 中选择语言，没有匹配时返回 fallback
+//   - 按 q 值从高到低匹配，q=0 的语言不会被选中
+//   - 逐级去掉子标签回退匹配：zh-Hant-TW -> zh-Hant -> zh
+//   - * 匹配第一个未被排除的支持语言
+//
+// 返回 supported 中的原始写法；supported 为空时返回客户端优先的语言
+func NegotiateLanguage(acceptLanguage string, supported []string, fallback string) string {
+	items := ParseAcceptLanguage(acceptLanguage)
+	// 语言标签不区分大小写，排除的语言按小写记录
+	excluded := make(map[string]bool)
+	for _, item := range items {
+		if item.Q == 0 {
+			excluded[strings.ToLower(item.Lang)] = true
+		}
+	}
 
-		langQ := strings.Split(trimedLangQStr, ";")
-		if len(langQ) == 1 {
-			lq := LangQ{langQ[0], 1}
-			lqs = append(lqs, lq)
-		} else {
-			qp := strings.Split(langQ[1], "=")
-			q, err := strconv.ParseFloat(qp[1], 64)
-			if err != nil {
-				panic(err)
+	for _, item := range items {
+		if item.Q == 0 {
+			continue
+		}
+		if item.Lang == "*" {
+			for _, lang := range supported {
+				if !excluded[strings.ToLower(lang)] {
+					return lang
+				}
+			}
+			continue
+		}
+		if len(supported) == 0 {
+			return item.Lang
+		}
+		for tag := item.Lang; tag != ""; tag = parentLanguage(tag) {
+			if excluded[strings.ToLower(tag)] && !strings.EqualFold(tag, item.Lang) {
+				break
+			}
+			for _, lang := range supported {
+				if strings.EqualFold(lang, tag) {
+					return lang
+				}
 			}
-			lq := LangQ{langQ[0], q}
-			lqs = append(lqs, lq)
 		}
 	}
-	return lqs
+	return fallback
 }
 
-// GetAcceptLanguage 获取语言  值返回排名第一的值
-func GetAcceptLanguage(acptlang string) string {
-	langs := ParseAcceptLanguage(acptlang)
-	Language := "en"
-	if len(langs) > 0 {
-		slices.SortFunc(langs, func(a, b LangQ) int {
-			if a.Q < b.Q {
-				return 1
-			}
-			return -1
-		})
-		Language = langs[0].Lang
+// parentLanguage 去掉最后一个子标签，如 zh-Hant-TW -> zh-Hant，没有子标签时返回空字符串（保持原始大小写）
+// 单字符子标签（如 x-private 中的 x）一并去掉
+func parentLanguage(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndexByte(tag, '-'); j >= 0 && len(tag)-j == 2 {
+		tag = tag[:j]
 	}
-	return Language
+	return tag
 }
 
-// ParseXForwardedFor ip链 解析
+// ParseXForwardedFor ip链 解析，去掉空格和空项，顺序为 客户端, 代理1, 代理2...
+// 客户端可以伪造这个请求头，获取客户端 IP 应使用 ClientIP
 func ParseXForwardedFor(xForwardedFor string) []string {
-	return strings.Split(xForwardedFor, ",")
+	var ips []string
+	for _, ip := range strings.Split(xForwardedFor, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
 }
@@ -0,0 +1,47 @@
+package echoApi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.NotPanics(t, func() {
+		assert.Equal(t, []LangQ{{Lang: "en", Q: 1}, {Lang: "fr", Q: 1}}, ParseAcceptLanguage("en;q, fr;q=abc"))
+	})
+	assert.Equal(t, []LangQ{{Lang: "zh-CN", Q: 1}, {Lang: "en", Q: 0.8}, {Lang: "*", Q: 0.1}, {Lang: "fr", Q: 0}},
+		ParseAcceptLanguage("fr;q=0, en;q=0.8, zh-CN, *;q=0.1"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+
+	assert.Equal(t, "en", GetAcceptLanguage(""))
+	assert.Equal(t, "en", GetAcceptLanguage("*, fr;q=0"))
+	assert.Equal(t, "zh-TW", GetAcceptLanguage("en;q=0.5, zh-TW;q=0.9"))
+}
+
+func TestNegotiateLanguage(t *testing.T) {
+	supported := []string{"en", "zh", "zh-Hant"}
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"zh-Hant-TW", "zh-Hant"},
+		{"zh-CN", "zh"},
+		{"fr, zh;q=0.5", "zh"},
+		{"fr", "en"},
+		{"zh-CN, zh;q=0, en;q=0.5", "en"},
+		{"*", "en"},
+		{"en;q=0, *", "zh"},
+		{"de;q=abc, zh", "zh"},
+		{"zh-hant-x-private", "zh-Hant"},
+		{"ZH-CN, zh;q=0, EN;q=0.5", "en"},
+		{"EN;q=0, *", "zh"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, NegotiateLanguage(tc.header, supported, "en"), tc.header)
+	}
+
+	// 未配置支持的语言时返回客户端优先的语言
+	assert.Equal(t, "fr-CA", NegotiateLanguage("en;q=0.5, fr-CA", nil, "en"))
+}
@@ -3,6 +3,7 @@ package middlers
 import (
 	"fmt"
 	"github.com/labstack/echo/v4"
+	"github.com/preceeder/echoApi"
 	"golang.org/x/time/rate"
 	"log/slog"
 	"time"
@@ -37,6 +38,13 @@ func Init() *RateLimiterTrie {
 
 }
 
+// ClientIPLimit 按客户端 IP 限流的 before 参数，IP 为受信任代理解析后的地址（见 echoApi.ClientIP）
+func ClientIPLimit(rateVal float64, burstVal int) func(c echo.Context) (float64, int, []string) {
+	return func(c echo.Context) (float64, int, []string) {
+		return rateVal, burstVal, []string{echoApi.ClientIP(c)}
+	}
+}
+
 func RateLimitMiddleware(before func(c echo.Context) (float64, int, []string),
 	after func(c echo.Context, limit *rate.Limiter) error) echo.MiddlewareFunc {
 	limit := Init()
@@ -58,18 +66,19 @@ func RateLimitMiddleware(before func(c echo.Context) (float64, int, []string),
 			}
 			keys := []string{c.Path(), c.Request().Method}
 			keys = append(keys, outKeys...)
-			node, newAdd := limit.GetAdd(keys...)
+			node, _ := limit.GetAdd(keys...)
+			node.mu.Lock()
 			node.Data.lastSeen = time.Now()
-			if newAdd {
-				node.mu.Lock()
-				node.Data.limit.SetLimit(rate.Limit(rateVal))
-				node.Data.limit.SetBurst(burstVal)
-				node.mu.Unlock()
+			if node.Data.limit == nil {
+				// 新增的节点还没有 limiter，按第一次请求的配置创建
+				node.Data.limit = rate.NewLimiter(rate.Limit(rateVal), burstVal)
 			}
+			limiter := node.Data.limit
+			node.mu.Unlock()
 			// 本身有锁， 而且node的删除是在这个节点3分钟不在调用的情况下才会有， 基本不会有并发问题
-			allowed := node.Data.limit.Allow()
+			allowed := limiter.Allow()
 			if !allowed {
-				return after(c, node.Data.limit)
+				return after(c, limiter)
 				//return c.JSON(429,
 				//	map[string]any{"code": 429, "message": "请求太快了，请稍后再试"})
 			}
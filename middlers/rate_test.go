@@ -3,6 +3,7 @@ package middlers
 import (
 	"fmt"
 	"github.com/labstack/echo/v4"
+	"github.com/preceeder/echoApi"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/time/rate"
 	"net/http"
@@ -53,7 +54,7 @@ func TestRateLimitMiddleware(t *testing.T) {
 	// 发起第二次请求（立即连续），应触发限流
 	req2 := httptest.NewRequest(http.MethodGet, "/test", nil)
 	rec2 := httptest.NewRecorder()
-	req1.RemoteAddr = "1.2.3.4"
+	req2.RemoteAddr = "1.2.3.4"
 
 	e.ServeHTTP(rec2, req2)
 	assert.Equal(t, http.StatusTooManyRequests, rec2.Code)
@@ -122,6 +123,34 @@ func TestRateLimitMiddleware_Concurrent(t *testing.T) {
 	assert.Greater(t, tooManyReqCount, int32(0)) // 应有部分被限流
 }
 
+func TestClientIPLimit(t *testing.T) {
+	assert.NoError(t, echoApi.SetTrustedProxies("10.0.0.0/8"))
+	t.Cleanup(func() { _ = echoApi.SetTrustedProxies() })
+
+	e := echo.New()
+	e.GET("/ip", func(c echo.Context) error {
+		return c.String(http.StatusOK, "OK")
+	}, RateLimitMiddleware(ClientIPLimit(1, 1), func(c echo.Context, limit *rate.Limiter) error {
+		return c.JSON(429, map[string]any{"message": "太快了，请稍后再试"})
+	}))
+
+	request := func(xff string) int {
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set(echo.HeaderXForwardedFor, xff)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// 经过受信任代理的请求按各自的客户端 IP 限流
+	assert.Equal(t, http.StatusOK, request("1.1.1.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("1.1.1.1"))
+	assert.Equal(t, http.StatusOK, request("2.2.2.2"))
+	// 伪造的最左侧地址不影响限流 key
+	assert.Equal(t, http.StatusTooManyRequests, request("9.9.9.9, 1.1.1.1"))
+}
+
 func BenchmarkRateLimitMiddleware(b *testing.B) {
 	e := echo.New()
 
@@ -2,7 +2,6 @@ package echoApi
 
 import (
 	"bytes"
-	"context"
 	"errors"
 	"fmt"
 	"github.com/labstack/echo/v4"
@@ -15,8 +14,8 @@ import (
 	"time"
 )
 
-// 约定 在echo.Context 中 插入 context = conetxt.Context{}, 携带reqeustId属性
 // BaseErrorMiddleware 全局 panic 捕获中间件（Echo 版本）
+// 同时生成 requestId，写入请求的 context.Context（通过 Context(c)、RequestID(ctx) 获取）
 func BaseErrorMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
@@ -31,21 +30,21 @@ func BaseErrorMiddleware() echo.MiddlewareFunc {
 					slog.Error("base panic",
 						"err", rec,
 						"trace", trace,
-						"requestId", c.Get("requestId"),
+						"requestId", requestId,
 						"method", c.Request().Method,
 						"uri", c.Request().URL.Path,
 					)
 
 					// 构造统一错误响应
-					htperr := BaseHttpError{
-						StatusCode: 500,
-						Message:    "system error",
-					}
-					_ = c.JSON(htperr.GetStatusCode(), htperr.GetResponse(requestId))
+					htperr := NewError("INTERNAL_ERROR")
+					_ = writeResponse(c, htperr.GetStatusCode(), responseBody(c, htperr, requestId))
 				}
 			}()
-			ctx := context.WithValue(context.Background(), "requestId", requestId)
-			c.Set("context", ctx)
+			// 基于请求的 context，客户端断开连接时取消
+			ctx := WithRequestID(c.Request().Context(), requestId)
+			ctx = WithRemoteIP(ctx, ResolveClientIP(c.Request()))
+			ctx = WithLanguage(ctx, NegotiateLanguage(c.Request().Header.Get("Accept-Language"), SupportedLanguages, DefaultLanguage))
+			setContext(c, ctx)
 			return next(c)
 		}
 	}
@@ -65,7 +64,7 @@ type CorsConfig struct {
 func DefaultCorsConfig() CorsConfig {
 	return CorsConfig{
 		AllowOrigins:     []string{"*"}, // 开发环境可以使用 "*"，生产环境应该指定具体域名
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"},
 		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Requested-With", "X-Username", "X-ChannelId"},
 		ExposeHeaders:    []string{"X-Username", "X-ChannelId"},
 		AllowCredentials: false, // 注意：当 AllowOrigins 包含 "*" 时，AllowCredentials 必须为 false
@@ -113,8 +112,8 @@ func CorsMiddleware(config CorsConfig) echo.MiddlewareFunc {
 				res.Header().Set(key, value)
 			}
 
-			// OPTIONS 请求直接返回
-			if req.Method == http.MethodOptions {
+			// 预检请求直接返回，其他 OPTIONS 请求交给路由中声明的 handler
+			if req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != "" {
 				return c.NoContent(http.StatusNoContent)
 			}
 
@@ -127,7 +126,7 @@ func EchoLogger(serverLogHide, hideServerMiddleLogHeaders bool) echo.MiddlewareF
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
-			ctx := c.Get("context").(context.Context)
+			ctx := Context(c)
 			// 执行后续处理
 			err := next(c)
 			cost := time.Since(start)
@@ -145,12 +144,12 @@ func EchoLogger(serverLogHide, hideServerMiddleLogHeaders bool) echo.MiddlewareF
 				headers = c.Request().Header
 			}
 
-			// 获取 client IP（可根据需要调整）
-			ip := c.RealIP()
+			// 获取 client IP（受信任代理解析后的地址，见 SetTrustedProxies）
+			ip := ClientIP(c)
 
 			slog.Info("",
 				"method", c.Request().Method,
-				"requestId", ctx.Value("requestId"),
+				"requestId", RequestID(ctx),
 				"status", c.Response().Status,
 				"ip", ip,
 				"headers", headers,
@@ -181,7 +180,7 @@ func (r *ResponseInterceptor) WriteHeader(code int) {
 func InterceptMiddleware(f func(c echo.Context, w *ResponseInterceptor) []byte) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ctx := c.Get("context").(context.Context)
+			ctx := Context(c)
 
 			// WebSocket 升级请求跳过响应拦截（避免干扰握手）
 			if c.Request().Header.Get("Upgrade") == "websocket" {
@@ -201,7 +200,7 @@ func InterceptMiddleware(f func(c echo.Context, w *ResponseInterceptor) []byte)
 
 			err := next(c)
 			if err != nil {
-				slog.Error("响应拦截前处理失败", "error", err.Error(), "requestId", ctx.Value("requestId"))
+				slog.Error("响应拦截前处理失败", "error", err.Error(), "requestId", RequestID(ctx))
 				return err
 			}
 			nb := f(c, writer)
@@ -209,7 +208,7 @@ func InterceptMiddleware(f func(c echo.Context, w *ResponseInterceptor) []byte)
 			writer.ResponseWriter.WriteHeader(writer.status)
 			_, err = writer.ResponseWriter.Write(nb)
 			if err != nil {
-				slog.Error("响应拦截后处理失败", "error", err.Error(), "requestId", c.Get("requestId"))
+				slog.Error("响应拦截后处理失败", "error", err.Error(), "requestId", RequestID(ctx))
 				return err
 			}
 			return nil
@@ -228,14 +227,12 @@ func EchoResponseAndRecoveryHandler(
 ) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			ctx := c.Get("context").(context.Context)
-
 			// WebSocket 升级请求跳过响应处理中间件（避免干扰握手）
 			if c.Request().Header.Get("Upgrade") == "websocket" {
 				return next(c)
 			}
 
-			requestId := ctx.Value("requestId").(string)
+			requestId := RequestID(Context(c))
 			var resStatus = http.StatusInternalServerError
 
 			defer func() {
@@ -245,17 +242,16 @@ func EchoResponseAndRecoveryHandler(
 						if errorResponseHandler != nil {
 							he = errorResponseHandler(c, he)
 						}
+						setResponseCookies(c, he)
 						statusCode := he.GetStatusCode()
-						_ = c.JSON(statusCode, he.GetResponse(requestId))
+						_ = writeResponse(c, statusCode, responseBody(c, he, requestId))
 						resStatus = statusCode
 					} else {
 						// 统一错误响应格式
-						_ = c.JSON(resStatus, BaseHttpError{
-							StatusCode: resStatus,
-							Code:       "INTERNAL_ERROR",
-							Message:    "内部服务器错误",
-							RequestId:  requestId,
-						}.GetResponse(requestId))
+						htperr := NewError("INTERNAL_ERROR")
+						htperr.StatusCode = resStatus
+						htperr.RequestId = requestId
+						_ = writeResponse(c, resStatus, responseBody(c, htperr, requestId))
 					}
 
 					slog.Error("Recovery from panic",
@@ -281,7 +277,7 @@ func EchoResponseAndRecoveryHandler(
 						StatusCode: er.Code,
 						Message:    err.Error(),
 					}
-					return c.JSON(htperr.StatusCode, htperr.GetResponse(requestId))
+					return writeResponse(c, htperr.StatusCode, responseBody(c, htperr, requestId))
 				} else {
 					panic(err)
 				}
@@ -294,14 +290,16 @@ func EchoResponseAndRecoveryHandler(
 					if errorResponseHandler != nil {
 						val = errorResponseHandler(c, val)
 					}
-					return c.JSON(val.GetStatusCode(), val.GetResponse(requestId))
+					setResponseCookies(c, val)
+					return writeResponse(c, val.GetStatusCode(), responseBody(c, val, requestId))
 				case HttpResponse:
 					if normalResponseHandler != nil {
 						val = normalResponseHandler(c, val)
 					}
-					return c.JSON(val.GetStatusCode(), val.GetResponse(requestId))
+					setResponseCookies(c, val)
+					return writeResponse(c, val.GetStatusCode(), responseBody(c, val, requestId))
 				default:
-					return c.JSON(http.StatusOK, val)
+					return writeResponse(c, http.StatusOK, val)
 				}
 			}
 
@@ -0,0 +1,120 @@
+package echoApi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorFormat 错误响应格式
+type ErrorFormat int
+
+const (
+	ErrorFormatDefault  ErrorFormat = iota // 继承上一级配置（路由 -> 实例信封 -> DefaultEnvelope），最终为 ErrorFormatEnvelope
+	ErrorFormatEnvelope                    // 信封格式 {requestId, code, message, details}
+	ErrorFormatProblem                     // RFC 9457 (RFC 7807) application/problem+json
+)
+
+// problem+json 的 MIME 类型
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+	MIMEApplicationProblemXML  = "application/problem+xml"
+)
+
+// ProblemDetails RFC 9457 错误响应体：type、title、status、detail、instance 以及扩展成员
+// 扩展成员：requestId（按信封的 key 配置）、code、errors（字段校验错误）、details（其他错误详情）
+type ProblemDetails map[string]any
+
+// errorFormatKey echo.Context 中保存路由错误响应格式的 key
+const errorFormatKey = "echoApi.errorFormat"
+
+// withErrorFormat 路由单独配置错误响应格式时，在 handler 中记录
+func withErrorFormat(format ErrorFormat, handler echo.HandlerFunc) echo.HandlerFunc {
+	if format == ErrorFormatDefault {
+		return handler
+	}
+	return func(c echo.Context) error {
+		c.Set(errorFormatKey, format)
+		return handler(c)
+	}
+}
+
+// errorFormatFor 请求使用的错误响应格式
+func errorFormatFor(c echo.Context, env *Envelope) ErrorFormat {
+	if format, ok := c.Get(errorFormatKey).(ErrorFormat); ok && format != ErrorFormatDefault {
+		return format
+	}
+	if env.ErrorFormat != ErrorFormatDefault {
+		return env.ErrorFormat
+	}
+	return ErrorFormatEnvelope
+}
+
+// problemDetails 将 HttpError 转换为 problem+json 响应体
+func problemDetails(c echo.Context, env *Envelope, herr HttpError, requestId string) ProblemDetails {
+	status := herr.GetStatusCode()
+	code, detail := "", herr.Error()
+	var details any
+	var base *BaseHttpError
+	switch v := herr.(type) {
+	case BaseHttpError:
+		base = &v
+	case *BaseHttpError:
+		base = v
+	}
+	if base != nil {
+		code, detail, details = base.Code, base.Message, base.Details
+	}
+
+	problemType := "about:blank"
+	if env.ProblemTypeBase != "" && code != "" {
+		problemType = strings.TrimSuffix(env.ProblemTypeBase, "/") + "/" + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+	}
+
+	p := ProblemDetails{
+		"type":     problemType,
+		"title":    http.StatusText(status),
+		"status":   status,
+		"detail":   detail,
+		"instance": c.Request().URL.Path,
+	}
+	env.set(p, env.RequestIdKey, "requestId", requestId)
+	if code != "" {
+		env.set(p, env.CodeKey, "code", code)
+	}
+	var fieldErrs ValidationErrors
+	if err, ok := details.(error); ok && errors.As(err, &fieldErrs) {
+		p["errors"] = fieldErrs
+	} else if details != nil {
+		env.set(p, env.DetailsKey, "details", details)
+	}
+	return p
+}
+
+// writeProblem 写出 problem+json 响应，按 Accept 选择 JSON 或 XML 等编码
+func writeProblem(c echo.Context, code int, p ProblemDetails) error {
+	mimeType, codec := negotiateCodec(c.Request().Header.Get(echo.HeaderAccept))
+	var data []byte
+	var err error
+	switch codec.(type) {
+	case JSONCodec:
+		mimeType = MIMEApplicationProblemJSON
+		data, err = json.Marshal(p)
+	default:
+		if _, ok := codec.(XMLCodec); ok {
+			mimeType = MIMEApplicationProblemXML
+		}
+		data, err = codec.Marshal(map[string]any(p))
+		if errors.Is(err, ErrUnsupportedType) {
+			mimeType = MIMEApplicationProblemJSON
+			data, err = json.Marshal(p)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return c.Blob(code, mimeType, data)
+}
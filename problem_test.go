@@ -0,0 +1,96 @@
+package echoApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTeapotError struct{}
+
+func (testTeapotError) GetStatusCode() int        { return http.StatusTeapot }
+func (testTeapotError) GetResponse(id string) any { return map[string]any{"id": id} }
+func (testTeapotError) Error() string             { return "teapot" }
+
+func testTeapot(c echo.Context, req *testPageReq) (any, error) {
+	return nil, testTeapotError{}
+}
+
+func TestProblemDetails(t *testing.T) {
+	srv := NewServer("/api")
+	srv.Envelope = &Envelope{ErrorFormat: ErrorFormatProblem, ProblemTypeBase: "https://errors.example.com/"}
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/create"}, testTypedCreate))
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodGet, Path: "/teapot"}, testTeapot))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	rec, res := doTestRequest(e, http.MethodPost, "/api/create?page=0", strings.NewReader(`{"email":"bad"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "https://errors.example.com/validation-failed", res["type"])
+	assert.Equal(t, "Bad Request", res["title"])
+	assert.Equal(t, float64(400), res["status"])
+	assert.Equal(t, "参数校验失败", res["detail"])
+	assert.Equal(t, "/api/create", res["instance"])
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+	assert.NotEmpty(t, res["requestId"])
+	assert.Len(t, res["errors"], 3)
+
+	// handler 返回的 HttpError
+	rec, res = doTestRequest(e, http.MethodPost, "/api/create?page=1", strings.NewReader(`{"name":"forbidden","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "https://errors.example.com/forbidden", res["type"])
+
+	// 自定义 HttpError 没有错误码
+	rec, res = doTestRequest(e, http.MethodGet, "/api/teapot", nil, "")
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "about:blank", res["type"])
+	assert.Equal(t, "teapot", res["detail"])
+	assert.Nil(t, res["code"])
+
+	// XML
+	req := httptest.NewRequest(http.MethodGet, "/api/teapot", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, MIMEApplicationProblemXML, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<status>418</status>")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/create?page=1", strings.NewReader(`{"name":"a","email":"bad"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, MIMEApplicationProblemXML, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), "<errors><field>email</field><rule>email</rule><message>必须是有效的邮箱地址</message></errors>")
+	assert.NotContains(t, rec.Body.String(), "<param>")
+}
+
+func TestProblemDetails_PerRoute(t *testing.T) {
+	srv := NewServer("/api")
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/problem", ErrorFormat: ErrorFormatProblem}, testTypedCreate))
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/envelope"}, testTypedCreate))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	rec, res := doTestRequest(e, http.MethodPost, "/api/problem?page=1", strings.NewReader(`{"name":"forbidden","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "about:blank", res["type"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/api/envelope?page=1", strings.NewReader(`{"name":"forbidden","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	assert.Nil(t, res["type"])
+	assert.Equal(t, "FORBIDDEN", res["code"])
+
+	// 路由配置优先于实例信封的配置
+	srv = NewServer("/api")
+	srv.Envelope = &Envelope{ErrorFormat: ErrorFormatProblem}
+	assert.NoError(t, Handle(srv, RouteBuilder{Method: http.MethodPost, Path: "/envelope", ErrorFormat: ErrorFormatEnvelope}, testTypedCreate))
+	e = srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+	_, res = doTestRequest(e, http.MethodPost, "/api/envelope?page=1", strings.NewReader(`{"name":"forbidden","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Nil(t, res["type"])
+	assert.Equal(t, "FORBIDDEN", res["code"])
+}
@@ -0,0 +1,213 @@
+package echoApi
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// QueryFieldInfo query 字段信息（与 DefaultFieldInfo 一样使用字段索引，避免运行时按名字查找）
+// 支持嵌套：?page.size=20、?page[size]=20 绑定到 Page.Size，?filter[status]=open 绑定到 map 字段
+type QueryFieldInfo struct {
+	FieldIndex []int  // 字段索引路径，嵌套结构体逐级索引
+	Key        string // 完整 key（点号形式），如 page.size；map 字段为前缀，如 filter
+	IsMap      bool   // map[string]T 字段，匹配 key.xxx
+	Split      bool   // query:"tags,split" 按逗号拆分
+	Format     string // format 标签
+}
+
+// buildQueryFieldsWithIndex 构建 query 字段列表（在 Register 时执行一次）
+// 匿名结构体字段的子字段直接提升，带 query 标签的结构体字段以标签名作为前缀
+func buildQueryFieldsWithIndex(t reflect.Type) []QueryFieldInfo {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return appendQueryFields(nil, t, nil, "", map[reflect.Type]bool{})
+}
+
+func appendQueryFields(fields []QueryFieldInfo, t reflect.Type, index []int, prefix string, seen map[reflect.Type]bool) []QueryFieldInfo {
+	if seen[t] {
+		return fields
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !isBindableEmbedded(field) {
+			continue
+		}
+
+		fieldIndex := append(index[:len(index):len(index)], i)
+		name, split := parseSourceTag(field.Tag.Get("query"))
+		structType := indirectType(field.Type)
+
+		switch {
+		case field.Anonymous && name == "" && isNestedQueryStruct(structType):
+			// 匿名字段：子字段提升到当前层级
+			fields = appendQueryFields(fields, structType, fieldIndex, prefix, seen)
+		case name == "":
+			continue
+		case field.Type.Kind() == reflect.Map && field.Type.Key().Kind() == reflect.String:
+			fields = append(fields, QueryFieldInfo{
+				FieldIndex: fieldIndex,
+				Key:        prefix + name,
+				IsMap:      true,
+				Split:      split,
+				Format:     field.Tag.Get("format"),
+			})
+		case isNestedQueryStruct(structType):
+			fields = appendQueryFields(fields, structType, fieldIndex, prefix+name+".", seen)
+		default:
+			fields = append(fields, QueryFieldInfo{
+				FieldIndex: fieldIndex,
+				Key:        prefix + name,
+				Split:      split,
+				Format:     field.Tag.Get("format"),
+			})
+		}
+	}
+	return fields
+}
+
+// isBindableEmbedded 未导出的匿名结构体（非指针）中导出的字段仍然可以设置，与 encoding/json 保持一致
+// 未导出的匿名结构体指针无法分配，不展开
+func isBindableEmbedded(field reflect.StructField) bool {
+	return field.Anonymous && field.Type.Kind() == reflect.Struct
+}
+
+// isNestedQueryStruct 判断结构体是否需要展开成子字段（time.Time、TextUnmarshaler 等整体绑定的类型除外）
+func isNestedQueryStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		t != fileHeaderType.Elem() &&
+		!t.ConvertibleTo(timeType) &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// bindQueryFields 按缓存的字段信息绑定 query 参数
+func bindQueryFields(target reflect.Value, fields []QueryFieldInfo, query url.Values) error {
+	if len(fields) == 0 || len(query) == 0 {
+		return nil
+	}
+	query = normalizeQuery(query)
+
+	for i := range fields {
+		info := &fields[i]
+		if info.IsMap {
+			if err := bindQueryMap(target, info, query); err != nil {
+				return err
+			}
+			continue
+		}
+
+		values := nonEmptyValues(query[info.Key])
+		if len(values) == 0 {
+			continue
+		}
+		if info.Split {
+			values = splitValues(values)
+		}
+
+		fieldValue := fieldByIndexAlloc(target, info.FieldIndex)
+		if err := setFieldValueFromStrings(fieldValue, fieldValue.Type(), values, info.Format); err != nil {
+			return fmt.Errorf("query 参数 %s 绑定失败: %w", info.Key, err)
+		}
+	}
+	return nil
+}
+
+// bindQueryMap 绑定 map 字段：?filter.status=open 或 ?filter[status]=open
+func bindQueryMap(target reflect.Value, info *QueryFieldInfo, query url.Values) error {
+	prefix := info.Key + "."
+	var fieldValue reflect.Value
+	for key, values := range query {
+		subKey, ok := strings.CutPrefix(key, prefix)
+		if !ok || subKey == "" {
+			continue
+		}
+		values = nonEmptyValues(values)
+		if len(values) == 0 {
+			continue
+		}
+		if info.Split {
+			values = splitValues(values)
+		}
+
+		// 有值时才分配 map，没有匹配的 key 时保持 nil
+		if !fieldValue.IsValid() {
+			fieldValue = fieldByIndexAlloc(target, info.FieldIndex)
+			if fieldValue.IsNil() {
+				fieldValue.Set(reflect.MakeMap(fieldValue.Type()))
+			}
+		}
+
+		mapType := fieldValue.Type()
+		elem := reflect.New(mapType.Elem()).Elem()
+		if err := setFieldValueFromStrings(elem, mapType.Elem(), values, info.Format); err != nil {
+			return fmt.Errorf("query 参数 %s 绑定失败: %w", key, err)
+		}
+		fieldValue.SetMapIndex(reflect.ValueOf(subKey).Convert(mapType.Key()), elem)
+	}
+	return nil
+}
+
+// fieldByIndexAlloc 按索引路径取字段，途经的 nil 结构体指针会被分配
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// normalizeQuery 将 filter[status]、a[b][c]、ids[] 统一为点号形式 filter.status、a.b.c、ids
+// 没有方括号时直接返回原始值，避免额外分配
+func normalizeQuery(query url.Values) url.Values {
+	needNormalize := false
+	for key := range query {
+		if strings.IndexByte(key, '[') >= 0 {
+			needNormalize = true
+			break
+		}
+	}
+	if !needNormalize {
+		return query
+	}
+
+	res := make(url.Values, len(query))
+	for key, values := range query {
+		nk := normalizeQueryKey(key)
+		res[nk] = append(res[nk], values...)
+	}
+	return res
+}
+
+func normalizeQueryKey(key string) string {
+	if strings.IndexByte(key, '[') < 0 {
+		return key
+	}
+	var b strings.Builder
+	b.Grow(len(key))
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '[':
+			// ids[] 表示数组，不产生新的层级
+			if i+1 < len(key) && key[i+1] != ']' {
+				b.WriteByte('.')
+			}
+		case ']':
+		default:
+			b.WriteByte(key[i])
+		}
+	}
+	return b.String()
+}
@@ -2,11 +2,18 @@ package echoApi
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/labstack/echo/v4"
 	"io"
 	"net/url"
 )
 
+// MaxLogBodyBytes 日志中记录的请求体最大字节数，超出部分截断
+var MaxLogBodyBytes = 4 << 10
+
+// requestBodyKey buildHandler 保存已读取请求体的 echo.Context key
+const requestBodyKey = "requestBody"
+
 type ParamsData struct {
 	Body  any
 	Query url.Values
@@ -15,14 +22,21 @@ type ParamsData struct {
 }
 
 // GetRequestParamsEcho 提取 Echo 请求参数
+// 优先使用 buildHandler 已读取的请求体；否则最多读取 MaxLogBodyBytes 字节，并保证后续读取仍能拿到完整的请求体
 func GetRequestParamsEcho(c echo.Context) ParamsData {
-	var body []byte
-	bo, err := io.ReadAll(c.Request().Body)
-	if err != nil {
-		body = []byte("")
-	} else {
-		body = bo
-		c.Request().Body = io.NopCloser(bytes.NewBuffer(body))
+	body, ok := c.Get(requestBodyKey).([]byte)
+	if !ok && c.Request().Body != nil {
+		req := c.Request()
+		bo, err := io.ReadAll(io.LimitReader(req.Body, int64(MaxLogBodyBytes)+1))
+		if err == nil {
+			body = bo
+		}
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(bo), req.Body), Closer: req.Body}
+	}
+
+	truncated := len(body) > MaxLogBodyBytes
+	if truncated {
+		body = body[:MaxLogBodyBytes]
 	}
 
 	// 解析 query 和 path 参数
@@ -35,17 +49,32 @@ func GetRequestParamsEcho(c echo.Context) ParamsData {
 		pathParams[name] = c.Param(name)
 	}
 
+	logBody := LogStr(body)
+	if truncated {
+		logBody += "...(truncated)"
+	}
+
 	return ParamsData{
-		Body:  LogStr(body),
+		Body:  logBody,
 		Query: query,
 		Url:   urlp,
 		Path:  pathParams,
 	}
 }
 
+// readCloser 组合已读出的部分和原始请求体
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
 // json 字符串可以不加转议符"\" 输出
+// 不是合法 JSON 时（如截断的 body、表单、二进制编码）按普通字符串输出
 type LogStr string
 
 func (d LogStr) MarshalJSON() ([]byte, error) {
+	if d == "" || !json.Valid([]byte(d)) {
+		return json.Marshal(string(d))
+	}
 	return []byte(d), nil
 }
@@ -10,16 +10,21 @@ type HttpResponse interface {
 var HttpResponseType = reflect.TypeOf((*HttpResponse)(nil)).Elem()
 
 type BaseHttpResponse struct {
-	StatusCode int    `json:"-"` // 默认情况下 http_code 和code 一致
-	RequestId  string `json:"requestId"`
-	Data       any    `json:"data"`
+	StatusCode int      `json:"-"` // 默认情况下 http_code 和code 一致
+	RequestId  string   `json:"requestId"`
+	Data       any      `json:"data"`
+	Message    string   `json:"message,omitempty"` // 为空时使用 Envelope.SuccessMessage
+	Meta       any      `json:"meta,omitempty"`    // 分页等附加信息，nil 时不输出
+	Cookies    []Cookie `json:"-"`                 // 需要设置的 cookie
 }
 
+// GetResponse 按 DefaultEnvelope 输出响应体（经过 EchoResponseAndRecoveryHandler 时使用路由所属实例的信封格式）
 func (h BaseHttpResponse) GetResponse(requestId string) any {
-	if h.StatusCode == 0 {
-		return map[string]any{"RequestId": requestId, "data": h.Data}
-	}
-	return map[string]any{"RequestId": requestId, "data": h.Data}
+	return h.envelopeBody(DefaultEnvelope, requestId)
+}
+
+func (h BaseHttpResponse) envelopeBody(env *Envelope, requestId string) any {
+	return env.success(requestId, h.Data, h.Meta, h.Message)
 }
 
 func (h BaseHttpResponse) GetStatusCode() int {
@@ -29,6 +34,10 @@ func (h BaseHttpResponse) GetStatusCode() int {
 	return 200
 }
 
+func (h BaseHttpResponse) GetCookies() []Cookie {
+	return h.Cookies
+}
+
 type HttpError interface {
 	GetStatusCode() int // 正常情况都是 200
 	GetResponse(string) any
@@ -42,10 +51,17 @@ type BaseHttpError struct {
 	Code       string `json:"code"`
 	Message    string `json:"message"`
 	RequestId  string `json:"requestId"`
+	Details    any    `json:"details,omitempty"` // 错误详情，如字段级校验错误
+	Args       []any  `json:"-"`                 // 错误码消息模板的参数（见 NewError），响应时按 Accept-Language 翻译
 }
 
+// GetResponse 按 DefaultEnvelope 输出响应体（经过 EchoResponseAndRecoveryHandler 时使用路由所属实例的信封格式）
 func (h BaseHttpError) GetResponse(requestId string) any {
-	return map[string]any{"requestId": requestId, "code": h.Code, "message": h.Message}
+	return h.envelopeBody(DefaultEnvelope, requestId)
+}
+
+func (h BaseHttpError) envelopeBody(env *Envelope, requestId string) any {
+	return env.failure(requestId, h.Code, h.Message, h.Details)
 }
 
 func (h BaseHttpError) Error() string {
@@ -0,0 +1,168 @@
+package echoApi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/coder/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// 路由检查发现的问题类型，可以通过 errors.Is 判断
+var (
+	ErrDuplicateRoute   = errors.New("重复的路由")
+	ErrAmbiguousRoute   = errors.New("路径参数冲突的路由")
+	ErrUnknownFunc      = errors.New("控制器中不存在该方法")
+	ErrInvalidSignature = errors.New("handler 签名不正确")
+	ErrInvalidRoute     = errors.New("路由配置错误")
+	ErrDuplicateName    = errors.New("路由名已被其他路径使用")
+)
+
+// RouteError 路由注册或挂载时发现的问题，Register、MountRoutes 通过 errors.Join 汇总后返回
+type RouteError struct {
+	Controller string // 控制器类型名
+	FuncName   string
+	Method     string
+	Path       string
+	Err        error // ErrDuplicateRoute 等，附带详细原因
+}
+
+func (e *RouteError) Error() string {
+	var b strings.Builder
+	if e.Controller != "" {
+		b.WriteString(e.Controller)
+		if e.FuncName != "" {
+			b.WriteString("." + e.FuncName)
+		}
+		b.WriteString(" ")
+	}
+	if e.Method != "" || e.Path != "" {
+		b.WriteString(strings.TrimSpace(e.Method+" "+e.Path) + " ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	echoContextType = reflect.TypeOf((*echo.Context)(nil)).Elem()
+	wsConnType      = reflect.TypeOf((*websocket.Conn)(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// checkHandlerSignature 检查控制器方法签名（methodType 包含 receiver）
+// HTTP：func(c echo.Context, params...) [result]，params 为结构体、结构体指针、io.Reader 或 context.Context
+// WebSocket：func(c echo.Context, conn *websocket.Conn) [error]
+func checkHandlerSignature(method string, methodType reflect.Type) error {
+	if methodType.NumIn() < 2 || methodType.In(1) != echoContextType {
+		return fmt.Errorf("%w: 第一个参数必须是 echo.Context", ErrInvalidSignature)
+	}
+
+	if method == "WS" {
+		if methodType.NumIn() < 3 || methodType.In(2) != wsConnType {
+			return fmt.Errorf("%w: WebSocket handler 第二个参数必须是 *websocket.Conn", ErrInvalidSignature)
+		}
+		return checkHandlerResults(methodType)
+	}
+
+	for i := 2; i < methodType.NumIn(); i++ {
+		paramType := methodType.In(i)
+		if isReaderParam(paramType) || paramType == contextType || indirectType(paramType).Kind() == reflect.Struct {
+			continue
+		}
+		return fmt.Errorf("%w: 第 %d 个参数 %s 必须是结构体、结构体指针、io.Reader 或 context.Context", ErrInvalidSignature, i, paramType)
+	}
+	return checkHandlerResults(methodType)
+}
+
+// checkHandlerResults 检查返回值：无返回值、T、error 或 (T, error)
+func checkHandlerResults(methodType reflect.Type) error {
+	switch methodType.NumOut() {
+	case 0, 1:
+		return nil
+	case 2:
+		if !methodType.Out(1).Implements(errorType) {
+			return fmt.Errorf("%w: 第二个返回值 %s 必须是 error", ErrInvalidSignature, methodType.Out(1))
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: 最多只能有两个返回值 (T, error), 当前有 %d 个", ErrInvalidSignature, methodType.NumOut())
+	}
+}
+
+// routePattern 将路径参数名统一，用于判断 /user/:id 与 /user/:name 这类冲突
+func routePattern(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, ":") {
+			segments[i] = ":"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// routeMethod 路由实际注册的 HTTP 方法（WebSocket 使用 GET）
+func routeMethod(method string) string {
+	method = strings.ToUpper(method)
+	if method == "WS" {
+		return "GET"
+	}
+	return method
+}
+
+// checkRouteConflict 检查两个路由是否冲突，不冲突返回 nil
+// 同一方法和路径的不同版本不算冲突；ANY 与同路径的任何方法冲突
+func checkRouteConflict(a, b Route, pathA, pathB string) error {
+	methodA, methodB := routeMethod(a.Method), routeMethod(b.Method)
+	if methodA != methodB && methodA != "ANY" && methodB != "ANY" {
+		return nil
+	}
+	if routePattern(pathA) != routePattern(pathB) {
+		return nil
+	}
+	if a.Version != "" && b.Version != "" && !sameVersion(a.Version, b.Version) {
+		return nil
+	}
+	if pathA == pathB {
+		return fmt.Errorf("%w: 与 %s %s 重复", ErrDuplicateRoute, b.Method, pathB)
+	}
+	return fmt.Errorf("%w: 与 %s %s 的路径参数名不同", ErrAmbiguousRoute, b.Method, pathB)
+}
+
+// checkMountedConflict 比较两个路由实际挂载的所有路径，包括路径版本
+// 路径版本直接对应一个版本，按无版本的路由比较
+func checkMountedConflict(config VersionConfig, a, b Route, baseA, baseB string) error {
+	if err := checkRouteConflict(a, b, baseA+a.Path, baseB+b.Path); err != nil {
+		return err
+	}
+	versionA, versionB := config.versionPath(baseA, a), config.versionPath(baseB, b)
+	plainA, plainB := a, b
+	plainA.Version, plainB.Version = "", ""
+	if versionA != "" {
+		if err := checkRouteConflict(plainA, b, versionA, baseB+b.Path); err != nil {
+			return err
+		}
+	}
+	if versionB != "" {
+		if err := checkRouteConflict(a, plainB, baseA+a.Path, versionB); err != nil {
+			return err
+		}
+	}
+	if versionA != "" && versionB != "" {
+		return checkRouteConflict(plainA, plainB, versionA, versionB)
+	}
+	return nil
+}
+
+func sameVersion(a, b string) bool {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	return va.compare(vb) == 0
+}
@@ -0,0 +1,130 @@
+package echoApi
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConflictCtrl struct{}
+
+func (t *testConflictCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/user/:id", FuncName: "Get"},
+			{Path: "/user/:name", FuncName: "Other"},
+			{Path: "/item", FuncName: "Get"},
+			{Path: "/item", FuncName: "Other"},
+			{Path: "/missing", FuncName: "Missing"},
+			{Path: "/bad", FuncName: "BadParam"},
+			{Path: "/bad2", FuncName: "BadResult"},
+			{Path: "/v", FuncName: "Get", Version: "1"},
+			{Path: "/v", FuncName: "Other", Version: "2"},
+		},
+		Custom: []RouteBuilder{{Path: "/none", FuncName: "Get"}},
+	}
+}
+
+func (t *testConflictCtrl) Get(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "get"}
+}
+
+func (t *testConflictCtrl) Other(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "other"}
+}
+
+func (t *testConflictCtrl) BadParam(c echo.Context, id int) HttpResponse {
+	return nil
+}
+
+func (t *testConflictCtrl) BadResult(c echo.Context) (HttpResponse, string) {
+	return nil, ""
+}
+
+func routeErrors(err error) []*RouteError {
+	var res []*RouteError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			var re *RouteError
+			if errors.As(e, &re) {
+				res = append(res, re)
+			}
+		}
+	}
+	return res
+}
+
+func TestRegister_RouteErrors(t *testing.T) {
+	srv := NewServer("/api")
+	err := srv.Register(&testConflictCtrl{})
+
+	assert.ErrorIs(t, err, ErrDuplicateRoute)
+	assert.ErrorIs(t, err, ErrAmbiguousRoute)
+	assert.ErrorIs(t, err, ErrUnknownFunc)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+	assert.ErrorIs(t, err, ErrInvalidRoute)
+
+	var got []string
+	for _, re := range routeErrors(err) {
+		assert.Equal(t, "testConflictCtrl", re.Controller)
+		got = append(got, re.FuncName+" "+re.Method+" "+re.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"Get  /none",
+		"BadParam GET /bad",
+		"BadResult GET /bad2",
+		"Missing GET /missing",
+		"Other GET /user/:name",
+		"Other GET /item",
+	}, got)
+
+	// 非严格模式下其余路由照常注册，冲突时先注册的生效
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+	rec, res := doTestRequest(e, http.MethodGet, "/api/item", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "get", res["data"])
+	rec, _ = doTestRequest(e, http.MethodGet, "/api/bad", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 不同版本不算冲突
+	rec, res = doTestRequest(e, http.MethodGet, "/api/v2/v", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "other", res["data"])
+}
+
+func TestRegister_Strict(t *testing.T) {
+	srv := &Server{BasePrefixPath: "/api", Strict: true}
+	assert.Error(t, srv.Register(&testConflictCtrl{}))
+	assert.Empty(t, srv.routes)
+}
+
+type testPrefixedCtrl struct{}
+
+func (t *testPrefixedCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/p/item", FuncName: "Name"},
+			{Path: "/api/p/item", FuncName: "Name", NoUseBasePrefixPath: true},
+		},
+	}
+}
+
+func (t *testPrefixedCtrl) Name(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "p"}
+}
+
+func TestMountRoutes_Conflict(t *testing.T) {
+	// 加上前缀后 /api/p/item 与 /p/item 重复，注册时无法发现
+	srv := NewServer("/api")
+	assert.NoError(t, srv.Register(&testPrefixedCtrl{}))
+
+	e := echo.New()
+	err := srv.MountRoutes(e)
+	assert.ErrorIs(t, err, ErrDuplicateRoute)
+
+	srv.Strict = true
+	assert.Panics(t, func() { srv.NewEcho() })
+}
@@ -0,0 +1,177 @@
+package echoApi
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RouteInfo 实际挂载的路由信息（由 Routes 返回，也是路由列表调试接口的输出）
+type RouteInfo struct {
+	Method      string            `json:"method"`
+	Path        string            `json:"path"`                  // 加上路由前缀后的最终路径
+	VersionPath string            `json:"versionPath,omitempty"` // 路径版本，如 /api/v2/user/info
+	Controller  string            `json:"controller"`
+	Handler     string            `json:"handler"`          // 控制器.方法名
+	Params      []string          `json:"params,omitempty"` // handler 参数类型（不含 echo.Context）
+	Middlewares []string          `json:"middlewares,omitempty"`
+	CtxParams   map[string]string `json:"ctxParams,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+	Name        string            `json:"name,omitempty"`
+}
+
+// Routes 实例实际挂载的路由（冲突被跳过的路由不包含在内），按路径、方法排序
+func (s *Server) Routes() []RouteInfo {
+	return s.routeInfos(s.basePrefixPath())
+}
+
+func (s *Server) routeInfos(prefix string) []RouteInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mounted, _ := s.resolveRoutes(prefix)
+	versioning := s.versioning()
+	infos := make([]RouteInfo, 0, len(mounted))
+	for _, m := range mounted {
+		info := RouteInfo{
+			Method:     strings.ToUpper(m.Method),
+			Path:       m.basePath + m.Path,
+			Controller: m.Controller,
+			Handler:    m.FuncName,
+			CtxParams:  m.CtxParams,
+			Version:    m.Version,
+			Deprecated: m.Deprecated,
+			Name:       m.Name,
+		}
+		if m.Controller != "" {
+			// 泛型 handler 没有控制器，FuncName 已包含包名
+			info.Handler = m.Controller + "." + m.FuncName
+		}
+		info.VersionPath = versioning.versionPath(m.basePath, m.Route)
+		if info.Method == "WS" {
+			info.Params = []string{wsConnType.String()}
+		}
+		for _, param := range m.Params {
+			info.Params = append(info.Params, param.Params.String())
+		}
+		for _, mw := range m.Middlewares {
+			info.Middlewares = append(info.Middlewares, funcName(mw))
+		}
+		infos = append(infos, info)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return infos[i].Method < infos[j].Method
+	})
+	return infos
+}
+
+// funcName 函数名，闭包返回创建它的函数名，如 echoApi.CorsMiddleware
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return name
+}
+
+// RouteListConfig 路由列表调试接口配置（Server.RouteList）
+// 接口必须受保护：Token 和 Authorize 都未配置时不会挂载
+type RouteListConfig struct {
+	Path      string                    // 接口路径，默认 /debug/routes（不加路由前缀）
+	Token     string                    // 请求头 x-debug-token 需与之一致
+	Authorize func(c echo.Context) bool // 自定义鉴权，配置后不再校验 Token
+}
+
+// DefaultRouteListPath 路由列表调试接口的默认路径
+const DefaultRouteListPath = "/debug/routes"
+
+// mountRouteList 挂载路由列表调试接口
+// 默认返回 JSON，?format=text 或 Accept 优先 text/plain 时返回文本表格
+func (s *Server) mountRouteList(e *echo.Echo, config RouteListConfig, prefix string) {
+	if config.Token == "" && config.Authorize == nil {
+		slog.Error("路由列表调试接口未配置 Token 或 Authorize，不挂载")
+		return
+	}
+	path := config.Path
+	if path == "" {
+		path = DefaultRouteListPath
+	}
+
+	e.GET(path, withEnvelope(s.Envelope, func(c echo.Context) error {
+		if !config.authorized(c) {
+			requestId := RequestID(Context(c))
+			htperr := NewError("UNAUTHORIZED")
+			htperr.RequestId = requestId
+			return writeResponse(c, htperr.StatusCode, responseBody(c, htperr, requestId))
+		}
+
+		routes := s.routeInfos(prefix)
+		if wantsText(c) {
+			return c.String(http.StatusOK, formatRoutes(routes))
+		}
+		return c.JSON(http.StatusOK, map[string]any{"routes": routes})
+	}))
+}
+
+func (config RouteListConfig) authorized(c echo.Context) bool {
+	if config.Authorize != nil {
+		return config.Authorize(c)
+	}
+	token := c.Request().Header.Get("x-debug-token")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(config.Token)) == 1
+}
+
+// wantsText 请求是否要求文本格式
+func wantsText(c echo.Context) bool {
+	if format := c.QueryParam("format"); format != "" {
+		return format == "text"
+	}
+	for _, item := range parseQualityList(c.Request().Header.Get(echo.HeaderAccept)) {
+		if item.Quality == 0 {
+			continue
+		}
+		return strings.EqualFold(item.Value, echo.MIMETextPlain)
+	}
+	return false
+}
+
+// formatRoutes 路由列表的文本表格
+func formatRoutes(routes []RouteInfo) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tPATH\tHANDLER\tPARAMS\tMIDDLEWARES\tVERSION")
+	for _, r := range routes {
+		version := r.Version
+		if r.Deprecated {
+			version += " (deprecated)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Method, r.Path, r.Handler,
+			strings.Join(r.Params, ", "), strings.Join(r.Middlewares, ", "), strings.TrimSpace(version))
+	}
+	w.Flush()
+	return b.String()
+}
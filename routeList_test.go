@@ -0,0 +1,67 @@
+package echoApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Routes(t *testing.T) {
+	srv := NewServer("/api")
+	assert.NoError(t, srv.Group("/v1", testTraceMiddleware("v1")).Register(&testGroupCtrl{}))
+	assert.NoError(t, srv.Register(&testUserV1Ctrl{}))
+	assert.NoError(t, srv.Register(&testAdminCtrl{}))
+
+	routes := srv.Routes()
+	byPath := make(map[string]RouteInfo)
+	for _, r := range routes {
+		byPath[r.Method+" "+r.Path] = r
+	}
+
+	info := byPath["GET /api/v1/info"]
+	assert.Equal(t, "testGroupCtrl.Info", info.Handler)
+	assert.Equal(t, []string{"echoApi.testTraceMiddleware", "echoApi.testTraceMiddleware"}, info.Middlewares)
+	assert.Equal(t, map[string]string{"scope": "route"}, info.CtxParams)
+	assert.Nil(t, info.Params)
+
+	info = byPath["GET /api/user/info"]
+	assert.Equal(t, "/api/v1/user/info", info.VersionPath)
+	assert.True(t, info.Deprecated)
+
+	info = byPath["POST /api/price"]
+	assert.Equal(t, []string{"echoApi.testPriceReq"}, info.Params)
+}
+
+func TestRouteList(t *testing.T) {
+	srv := NewServer("/api")
+	srv.RouteList = &RouteListConfig{Token: "secret"}
+	assert.NoError(t, srv.Register(&testGroupCtrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware())
+
+	rec, _ := doTestRequest(e, http.MethodGet, DefaultRouteListPath, nil, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, DefaultRouteListPath, nil)
+	req.Header.Set("x-debug-token", "secret")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"path":"/api/info"`)
+
+	req = httptest.NewRequest(http.MethodGet, DefaultRouteListPath, nil)
+	req.Header.Set("x-debug-token", "secret")
+	req.Header.Set(echo.HeaderAccept, "text/plain, application/json;q=0.5")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMETextPlain)
+	assert.Contains(t, rec.Body.String(), "GET     /api/info  testGroupCtrl.Info")
+
+	// 未配置鉴权时不挂载
+	srv.RouteList = &RouteListConfig{}
+	e = srv.NewEcho(BaseErrorMiddleware())
+	rec, _ = doTestRequest(e, http.MethodGet, DefaultRouteListPath, nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -2,19 +2,20 @@ package echoApi
 
 import (
 	"bytes"
-	"context"
-	"encoding/json"
+	"encoding"
+	"errors"
 	"fmt"
 	"github.com/coder/websocket"
 	"github.com/labstack/echo/v4"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 )
 
 // DefaultFieldInfo 默认值字段信息（优化：使用索引而非字段名）
@@ -27,7 +28,13 @@ type ParamBinding struct {
 	Params        reflect.Type       // 原始参数类型（可能是指针）
 	ElemType      reflect.Type       // 元素类型（如果是指针，则为指向的类型）
 	IsPtr         bool               // 是否为指针类型（预计算，避免运行时判断）
+	IsReader      bool               // io.Reader / io.ReadCloser 参数，直接传入请求体流，不做绑定
+	IsContext     bool               // context.Context 参数，传入请求的 context（见 Context）
 	DefaultFields []DefaultFieldInfo // 按字段索引的默认值（性能优化）
+	QueryFields   []QueryFieldInfo   // 按字段索引的 query 绑定信息（支持嵌套）
+	SourceFields  []SourceFieldInfo  // 按字段索引的 header/cookie/form/ip 绑定信息（匿名结构体的子字段展开）
+	Body          *bodyBinding       // body 绑定信息（没有 body 字段时为 nil）
+	Validator     *structValidator   // binding 标签编译后的校验规则（无规则时为 nil）
 }
 
 type Route struct {
@@ -38,11 +45,26 @@ type Route struct {
 	Middlewares         []echo.MiddlewareFunc // 接口中间件
 	NoUseBasePrefixPath bool                  // 是否禁用 BasePrefixPath
 	CtxParams           map[string]string     // 可以写入 ctx 的数据
+	MaxMultipartMemory  int64                 // multipart 解析的内存上限（字节）
+	BindMode            BindMode              // body 绑定模式
+	DisallowUnknown     bool                  // 是否拒绝 body 中未声明的字段
+	MaxBodyBytes        int64                 // 请求体大小上限（字节）
+	Controller          string                // 控制器类型名
+	FuncName            string                // 控制器方法名
+	Version             string                // API 版本，为空表示不参与版本选择
+	Deprecated          bool                  // 是否已废弃（响应带 Deprecation 头）
+	Sunset              time.Time             // 下线时间（响应带 Sunset 头）
+	Name                string                // 路由名，用于 URLFor 反向生成 URL
+	ErrorFormat         ErrorFormat           // 错误响应格式，ErrorFormatDefault 表示使用实例信封的配置
+
+	build    func(route Route) echo.HandlerFunc // 泛型 handler（Handle）的核心处理器，不为 nil 时不使用 Handler 反射调用
+	envelope *Envelope                          // 所属实例的响应信封格式
 }
 
 // RouteBuilder 路由构建器，提供类型安全的路由配置
 type RouteBuilder struct {
-	Method              string
+	Method              string   // 自定义方法路由（RouteConfig.Custom）中指定 HTTP 方法，如 PROPFIND
+	Methods             []string // 自定义方法路由中同时注册多个 HTTP 方法，如 []string{"GET", "POST"}
 	Path                string
 	FuncName            any // 支持 string 或函数引用（如 a.GetTime）
 	Middlewares         []echo.MiddlewareFunc
@@ -50,16 +72,30 @@ type RouteBuilder struct {
 	UseModel            bool // 是否使用模型名作为路径前缀
 	NoUseBasePrefixPath bool
 	CtxParams           map[string]string
+	MaxMultipartMemory  int64       // multipart 解析的内存上限（字节），0 表示继承 Global 或使用 DefaultMaxMultipartMemory
+	BindMode            BindMode    // body 绑定模式，BindModeDefault 表示继承 Global 或使用 DefaultBindMode
+	DisallowUnknown     bool        // 是否拒绝 body 中未声明的字段（Global 或 DefaultDisallowUnknownFields 开启时同样生效）
+	MaxBodyBytes        int64       // 请求体大小上限（字节），0 表示继承 Global 或使用 DefaultMaxBodyBytes，< 0 表示不限制
+	Version             string      // API 版本，如 "1"、"2.1"，为空时继承 Global；同一方法和路径可以声明多个版本
+	Deprecated          bool        // 是否已废弃，响应带 Deprecation 头（Global 开启时同样生效）
+	Sunset              time.Time   // 下线时间，响应带 Sunset 头，零值时继承 Global
+	Name                string      // 路由名，用于 URLFor 反向生成 URL；不继承 Global，同名路由的路径必须相同（如同一路径的多个方法、版本）
+	ErrorFormat         ErrorFormat // 错误响应格式（如 ErrorFormatProblem），ErrorFormatDefault 表示继承 Global 或实例信封的配置
 }
 
 // RouteConfig 路由配置，支持全局和按方法配置
 type RouteConfig struct {
-	Global *RouteBuilder // 全局配置
-	GET    []RouteBuilder
-	POST   []RouteBuilder
-	PUT    []RouteBuilder
-	DELETE []RouteBuilder
-	WS     []RouteBuilder // WebSocket 路由
+	Global  *RouteBuilder // 全局配置
+	GET     []RouteBuilder
+	POST    []RouteBuilder
+	PUT     []RouteBuilder
+	DELETE  []RouteBuilder
+	PATCH   []RouteBuilder
+	HEAD    []RouteBuilder
+	OPTIONS []RouteBuilder
+	Any     []RouteBuilder // 匹配所有 HTTP 方法
+	Custom  []RouteBuilder // 通过 RouteBuilder.Method / Methods 指定任意方法（包括 PROPFIND 等非标准方法）
+	WS      []RouteBuilder // WebSocket 路由
 }
 
 // Controller 控制器接口，所有控制器必须实现
@@ -67,66 +103,12 @@ type Controller interface {
 	RouteConfig() RouteConfig
 }
 
-// BasePrefixPath 全局的路由前缀
+// BasePrefixPath 默认实例的路由前缀，DefaultServer().BasePrefixPath 不为空时以其为准（其他实例使用 Server.BasePrefixPath）
 var BasePrefixPath = "/api"
 
-// routes 路由集合（使用互斥锁保护）
-var (
-	routes   []Route
-	routesMu sync.RWMutex
-)
-
-// Register 注册控制器（类型安全版本）
+// Register 注册控制器到默认实例
 func Register(ctrl Controller) error {
-	ctrlType := reflect.TypeOf(ctrl)
-	if ctrlType.Kind() != reflect.Ptr {
-		return fmt.Errorf("controller must be a pointer, got %T", ctrl)
-	}
-
-	ctrlValue := reflect.ValueOf(ctrl)
-	module := getModuleName(ctrlType)
-
-	config := ctrl.RouteConfig()
-
-	// 将路由扁平化为 map[方法名][]RouteBuilder
-	pathMap := expandRouteConfig(config, strings.ToLower(module))
-
-	// 遍历控制器方法
-	methodCount := ctrlType.NumMethod()
-	for i := 0; i < methodCount; i++ {
-		method := ctrlValue.Method(i)
-		methodType := ctrlType.Method(i)
-		actionName := methodType.Name
-
-		builders, exists := pathMap[actionName]
-		if !exists {
-			continue
-		}
-
-		for _, builder := range builders {
-			params, err := buildParamBindings(methodType)
-			if err != nil {
-				slog.Error("构建参数绑定失败", "method", actionName, "error", err)
-				continue
-			}
-
-			route := Route{
-				Path:                builder.Path,
-				Method:              builder.Method,
-				Handler:             method,
-				Params:              params,
-				Middlewares:         builder.Middlewares,
-				NoUseBasePrefixPath: builder.NoUseBasePrefixPath,
-				CtxParams:           builder.CtxParams,
-			}
-
-			routesMu.Lock()
-			routes = append(routes, route)
-			routesMu.Unlock()
-		}
-	}
-
-	return nil
+	return defaultServer.Register(ctrl)
 }
 
 // getModuleName 获取模块名（去掉包名前缀）
@@ -170,133 +152,274 @@ func extractFuncName(funcName any) string {
 	return ""
 }
 
-// hasJsonField 检查结构体类型是否包含需要从 body 绑定的字段（有 json 标签且没有 query/param 标签）
-func hasJsonField(t reflect.Type) bool {
+// isBodyField 判断字段是否从 body 绑定：有 json 标签，且没有 query/param/header/ip 等其他来源标签
+func isBodyField(field reflect.StructField) bool {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "" || jsonTag == "-" {
+		return false
+	}
+	return field.Tag.Get("query") == "" && field.Tag.Get("param") == "" && field.Tag.Get("header") == "" && field.Tag.Get("cookie") == "" && field.Tag.Get("ip") == ""
+}
+
+// SourceFieldInfo header/cookie/form/ip 字段信息（与 QueryFieldInfo 一样使用字段索引，在 Register 时构建一次）
+type SourceFieldInfo struct {
+	FieldIndex  []int        // 字段索引路径，匿名结构体的子字段逐级索引
+	Name        string       // 字段名（用于错误信息）
+	Type        reflect.Type // 字段类型
+	Header      string       // header 标签
+	Cookie      string       // cookie 标签
+	CookieSplit bool
+	Form        string // form 标签
+	FormSplit   bool
+	ClientIP    bool   // ip:"client"，绑定客户端 IP
+	Format      string // format 标签
+}
+
+// buildSourceFieldsWithIndex 构建 header/cookie/form/ip 字段列表
+// 没有这些标签的匿名结构体字段（如嵌入的 DefaultHeader）的子字段直接提升，与 query 一致
+func buildSourceFieldsWithIndex(t reflect.Type) []SourceFieldInfo {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
 	if t.Kind() != reflect.Struct {
-		return false
+		return nil
+	}
+	return appendSourceFields(nil, t, nil, map[reflect.Type]bool{})
+}
+
+func appendSourceFields(fields []SourceFieldInfo, t reflect.Type, index []int, seen map[reflect.Type]bool) []SourceFieldInfo {
+	if seen[t] {
+		return fields
 	}
+	seen[t] = true
+	defer delete(seen, t)
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		// 检查是否有 json 标签，且没有 query 和 param 标签
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" && jsonTag != "-" {
-			if field.Tag.Get("query") == "" && field.Tag.Get("param") == "" {
-				return true
+		if !field.IsExported() && !isBindableEmbedded(field) {
+			continue
+		}
+
+		fieldIndex := append(index[:len(index):len(index)], i)
+		info := SourceFieldInfo{
+			FieldIndex: fieldIndex,
+			Name:       field.Name,
+			Type:       field.Type,
+			ClientIP:   field.Tag.Get("ip") == "client",
+			Format:     field.Tag.Get("format"),
+		}
+		info.Header, _ = parseSourceTag(field.Tag.Get("header"))
+		info.Cookie, info.CookieSplit = parseSourceTag(field.Tag.Get("cookie"))
+		info.Form, info.FormSplit = parseSourceTag(field.Tag.Get("form"))
+
+		switch {
+		case info.ClientIP || info.Header != "" || info.Cookie != "" || info.Form != "":
+			if field.IsExported() {
+				fields = append(fields, info)
 			}
+		case field.Anonymous && isNestedQueryStruct(indirectType(field.Type)):
+			fields = appendSourceFields(fields, indirectType(field.Type), fieldIndex, seen)
+		}
+	}
+	return fields
+}
+
+// hasFormField 参数是否包含 form 标签的字段（包括匿名结构体的子字段）
+func (p *ParamBinding) hasFormField() bool {
+	for i := range p.SourceFields {
+		if p.SourceFields[i].Form != "" {
+			return true
 		}
 	}
 	return false
 }
 
 // bindParamPrecise 精确绑定参数，根据字段标签从不同源绑定，避免冲突
-func bindParamPrecise(c echo.Context, target interface{}, paramType reflect.Type, bodyBytes []byte) error {
-	if paramType.Kind() == reflect.Ptr {
-		paramType = paramType.Elem()
-	}
+// 优先级：param > query > header > cookie > form > json（路径参数 > 查询参数 > 请求头 > cookie > 表单 > body）
+// form 为解析后的表单数据，非表单请求时为 nil；opts 控制 body 的严格程度
+// ip:"client" 标签的字段绑定客户端 IP（见 ClientIP）
+func bindParamPrecise(c echo.Context, target interface{}, binding *ParamBinding, bodyBytes []byte, form *requestForm, opts bindOptions) error {
+	paramType := binding.ElemType
 	if paramType.Kind() != reflect.Struct {
 		return fmt.Errorf("参数类型必须是结构体")
 	}
 
 	targetValue := reflect.ValueOf(target).Elem()
-	queryParams := c.QueryParams()
-
-	// 解析 body（如果需要）
-	var bodyMap map[string]interface{}
-	needBody := false
-	for i := 0; i < paramType.NumField(); i++ {
-		field := paramType.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag != "" && jsonTag != "-" {
-			if field.Tag.Get("query") == "" && field.Tag.Get("param") == "" {
-				needBody = true
-				break
-			}
+	header := c.Request().Header
+	var cookies url.Values // 有 cookie 字段时才解析
+
+	// body 优先级最低，先直接解码到参数结构体，之后由 header、cookie、form、query、param 覆盖
+	if binding.Body != nil && len(bodyBytes) > 0 {
+		codec := opts.Codec
+		if codec == nil {
+			codec = JSONCodec{}
 		}
-	}
-
-	if needBody && len(bodyBytes) > 0 {
-		if err := json.Unmarshal(bodyBytes, &bodyMap); err != nil {
-			// body 解析失败不是致命错误，继续处理其他字段
-			bodyMap = make(map[string]interface{})
+		if err := binding.Body.decode(codec, bodyBytes, targetValue); err != nil && opts.Strict {
+			return newBodyDecodeError(err)
 		}
 	}
 
-	// 遍历字段，根据标签从不同源绑定
-	for i := 0; i < paramType.NumField(); i++ {
-		field := paramType.Field(i)
-		fieldValue := targetValue.Field(i)
+	// 按缓存的字段信息绑定 header、cookie、form 来源（包括匿名结构体的子字段）
+	for i := range binding.SourceFields {
+		info := &binding.SourceFields[i]
 
-		if !fieldValue.CanSet() {
+		if info.ClientIP {
+			// 客户端 IP（受信任代理解析后的地址，见 ClientIP），不从请求数据绑定
+			fieldValue := fieldByIndexAlloc(targetValue, info.FieldIndex)
+			if err := setFieldValueFromStrings(fieldValue, info.Type, []string{ClientIP(c)}, ""); err != nil {
+				return fmt.Errorf("字段 %s (ip:client) 绑定失败: %w", info.Name, err)
+			}
 			continue
 		}
 
-		queryTag := field.Tag.Get("query")
-		jsonTag := field.Tag.Get("json")
-		paramTag := field.Tag.Get("param")
-
-		// 优先级：param > query > json（路径参数 > 查询参数 > body）
-		if paramTag != "" && paramTag != "-" {
-			// 从路径参数绑定
-			if paramValue := c.Param(paramTag); paramValue != "" {
-				if err := setFieldValueFromString(fieldValue, field.Type, paramValue); err != nil {
-					return fmt.Errorf("字段 %s (param:%s) 绑定失败: %w", field.Name, paramTag, err)
+		if info.Header != "" {
+			// 从请求头绑定（Values 内部会做规范化，大小写不敏感）
+			if headerValues := header.Values(info.Header); len(headerValues) > 0 {
+				if isSliceField(info.Type) {
+					headerValues = splitValues(headerValues)
+				}
+				fieldValue := fieldByIndexAlloc(targetValue, info.FieldIndex)
+				if err := setFieldValueFromStrings(fieldValue, info.Type, headerValues, info.Format); err != nil {
+					return fmt.Errorf("字段 %s (header:%s) 绑定失败: %w", info.Name, info.Header, err)
 				}
 				continue
 			}
 		}
 
-		if queryTag != "" && queryTag != "-" {
-			// 从 query 参数绑定
-			if queryValue := queryParams.Get(queryTag); queryValue != "" {
-				if err := setFieldValueFromString(fieldValue, field.Type, queryValue); err != nil {
-					return fmt.Errorf("字段 %s (query:%s) 绑定失败: %w", field.Name, queryTag, err)
+		if info.Cookie != "" {
+			// 从 cookie 绑定，类型转换与 query 一致
+			if cookies == nil {
+				cookies = requestCookieValues(c.Request())
+			}
+			if cookieValues := nonEmptyValues(cookies[info.Cookie]); len(cookieValues) > 0 {
+				if info.CookieSplit {
+					cookieValues = splitValues(cookieValues)
+				}
+				fieldValue := fieldByIndexAlloc(targetValue, info.FieldIndex)
+				if err := setFieldValueFromStrings(fieldValue, info.Type, cookieValues, info.Format); err != nil {
+					return fmt.Errorf("字段 %s (cookie:%s) 绑定失败: %w", info.Name, info.Cookie, err)
 				}
 				continue
 			}
 		}
 
-		if jsonTag != "" && jsonTag != "-" && queryTag == "" && paramTag == "" {
-			// 从 body 绑定（只有 json 标签，没有 query/param 标签）
-			jsonName := strings.Split(jsonTag, ",")[0]
-			if bodyValue, exists := bodyMap[jsonName]; exists {
-				if err := setFieldValueFromJSON(fieldValue, field.Type, bodyValue); err != nil {
-					return fmt.Errorf("字段 %s (json:%s) 绑定失败: %w", field.Name, jsonName, err)
-				}
+		if info.Form != "" && form.has(info.Form) {
+			// 从表单绑定（包括上传文件）
+			fieldValue := fieldByIndexAlloc(targetValue, info.FieldIndex)
+			if _, err := setFieldValueFromForm(fieldValue, info.Type, form, info.Form, info.FormSplit, info.Format); err != nil {
+				return fmt.Errorf("字段 %s (form:%s) 绑定失败: %w", info.Name, info.Form, err)
+			}
+		}
+	}
+
+	// query 覆盖 header、form、json（支持嵌套结构体和 map）
+	if err := bindQueryFields(targetValue, binding.QueryFields, c.QueryParams()); err != nil {
+		return err
+	}
+
+	// 路径参数优先级最高，最后绑定
+	if len(c.ParamNames()) > 0 {
+		for i := 0; i < paramType.NumField(); i++ {
+			field := paramType.Field(i)
+			paramTag, _ := parseSourceTag(field.Tag.Get("param"))
+			if paramTag == "" {
 				continue
 			}
+			fieldValue := targetValue.Field(i)
+			if !fieldValue.CanSet() {
+				continue
+			}
+			if paramValue := c.Param(paramTag); paramValue != "" {
+				if err := setFieldValueFromString(fieldValue, field.Type, paramValue, field.Tag.Get("format")); err != nil {
+					return fmt.Errorf("字段 %s (param:%s) 绑定失败: %w", field.Name, paramTag, err)
+				}
+			}
 		}
 	}
 
 	return nil
 }
 
-// setFieldValueFromString 根据类型设置字段值（用于 query 和 param）
-func setFieldValueFromString(fieldValue reflect.Value, fieldType reflect.Type, strValue string) error {
+// parseSourceTag 解析来源标签，如 query:"tags,split" 返回 ("tags", true)
+// split 选项表示按逗号拆分值（?tags=a,b 等价于 ?tags=a&tags=b）
+func parseSourceTag(tag string) (string, bool) {
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	split := false
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == "split" {
+			split = true
+		}
+	}
+	return name, split
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setFieldValueFromString 根据类型设置字段值（用于 query、param、header、form）
+// format 为字段的 format 标签，用于 time.Time 的解析布局（默认 RFC3339，支持 unix、unixmilli）
+func setFieldValueFromString(fieldValue reflect.Value, fieldType reflect.Type, strValue string, format string) error {
 	if !fieldValue.CanSet() {
 		return fmt.Errorf("字段不可设置")
 	}
 
+	// 指针：有值时才分配，缺失的参数保持 nil
+	if fieldType.Kind() == reflect.Ptr {
+		ptr := reflect.New(fieldType.Elem())
+		if err := setFieldValueFromString(ptr.Elem(), fieldType.Elem(), strValue, format); err != nil {
+			return err
+		}
+		fieldValue.Set(ptr)
+		return nil
+	}
+
+	// time.Time（以及指定了 format 的 Date 等 time.Time 派生类型）
+	if fieldType == timeType || (format != "" && fieldType.Kind() == reflect.Struct && fieldType.ConvertibleTo(timeType)) {
+		t, err := parseTime(strValue, format)
+		if err != nil {
+			return err
+		}
+		fieldValue.Set(reflect.ValueOf(t).Convert(fieldType))
+		return nil
+	}
+
+	if fieldType == durationType {
+		d, err := time.ParseDuration(strValue)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(int64(d))
+		return nil
+	}
+
+	// 实现了 encoding.TextUnmarshaler 的类型（如 Date、net.IP、自定义枚举）
+	if reflect.PointerTo(fieldType).Implements(textUnmarshalerType) {
+		return fieldValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(strValue))
+	}
+
 	switch fieldType.Kind() {
 	case reflect.String:
 		fieldValue.SetString(strValue)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val, err := strconv.ParseInt(strValue, 10, 64)
+		val, err := strconv.ParseInt(strValue, 10, fieldType.Bits())
 		if err != nil {
 			return err
 		}
 		fieldValue.SetInt(val)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		val, err := strconv.ParseUint(strValue, 10, 64)
+		val, err := strconv.ParseUint(strValue, 10, fieldType.Bits())
 		if err != nil {
 			return err
 		}
 		fieldValue.SetUint(val)
 	case reflect.Float32, reflect.Float64:
-		val, err := strconv.ParseFloat(strValue, 64)
+		val, err := strconv.ParseFloat(strValue, fieldType.Bits())
 		if err != nil {
 			return err
 		}
@@ -307,49 +430,104 @@ func setFieldValueFromString(fieldValue reflect.Value, fieldType reflect.Type, s
 			return err
 		}
 		fieldValue.SetBool(val)
+	case reflect.Slice:
+		if fieldType.Elem().Kind() == reflect.Uint8 {
+			fieldValue.SetBytes([]byte(strValue))
+			return nil
+		}
+		return setFieldValueFromStrings(fieldValue, fieldType, []string{strValue}, format)
 	default:
-		// 对于复杂类型，使用 Echo 的默认绑定
-		return fmt.Errorf("不支持的字段类型: %v", fieldType.Kind())
+		return fmt.Errorf("不支持的字段类型: %v", fieldType)
 	}
 	return nil
 }
 
-// setFieldValueFromJSON 从 JSON 值设置字段值
-func setFieldValueFromJSON(fieldValue reflect.Value, fieldType reflect.Type, jsonValue interface{}) error {
-	if !fieldValue.CanSet() {
-		return fmt.Errorf("字段不可设置")
+// parseTime 按 format 解析时间
+func parseTime(strValue, format string) (time.Time, error) {
+	switch format {
+	case "", "RFC3339":
+		return time.Parse(time.RFC3339, strValue)
+	case "unix", "unixmilli":
+		n, err := strconv.ParseInt(strValue, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if format == "unix" {
+			return time.Unix(n, 0), nil
+		}
+		return time.UnixMilli(n), nil
+	default:
+		return time.ParseInLocation(format, strValue, time.Local)
 	}
+}
 
-	// 如果类型匹配，直接设置
-	if jsonVal := reflect.ValueOf(jsonValue); jsonVal.Type().AssignableTo(fieldType) {
-		fieldValue.Set(jsonVal)
+// isSliceField 判断字段是否按多值绑定（[]byte 以及实现了 TextUnmarshaler 的切片类型除外）
+func isSliceField(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.Uint8 &&
+		!reflect.PointerTo(fieldType).Implements(textUnmarshalerType)
+}
+
+// setFieldValueFromStrings 根据类型设置多值字段（切片逐个转换，非切片取第一个值）
+func setFieldValueFromStrings(fieldValue reflect.Value, fieldType reflect.Type, strValues []string, format string) error {
+	if len(strValues) == 0 {
 		return nil
 	}
+	if !isSliceField(fieldType) {
+		return setFieldValueFromString(fieldValue, fieldType, strValues[0], format)
+	}
 
-	// 如果类型可转换，尝试转换
-	if jsonVal := reflect.ValueOf(jsonValue); jsonVal.Type().ConvertibleTo(fieldType) {
-		fieldValue.Set(jsonVal.Convert(fieldType))
+	// 切片指针：分配后绑定到指向的切片
+	if fieldType.Kind() == reflect.Ptr {
+		ptr := reflect.New(fieldType.Elem())
+		if err := setFieldValueFromStrings(ptr.Elem(), fieldType.Elem(), strValues, format); err != nil {
+			return err
+		}
+		fieldValue.Set(ptr)
 		return nil
 	}
 
-	// 对于复杂类型，使用 JSON 序列化/反序列化
-	jsonBytes, err := json.Marshal(jsonValue)
-	if err != nil {
-		return err
+	slice := reflect.MakeSlice(fieldType, len(strValues), len(strValues))
+	for i, s := range strValues {
+		if err := setFieldValueFromString(slice.Index(i), fieldType.Elem(), s, format); err != nil {
+			return fmt.Errorf("第 %d 个值: %w", i, err)
+		}
 	}
+	fieldValue.Set(slice)
+	return nil
+}
 
-	ptr := reflect.New(fieldType)
-	if err := json.Unmarshal(jsonBytes, ptr.Interface()); err != nil {
-		return err
+// splitValues 按逗号拆分每个值并去掉空白（如 "a, b" 与两个独立的值等价）
+func splitValues(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		res = append(res, splitComma(v)...)
 	}
+	return res
+}
 
-	fieldValue.Set(ptr.Elem())
-	return nil
+// nonEmptyValues 过滤空字符串（?ids=&ids=1 只保留 1）
+func nonEmptyValues(values []string) []string {
+	for _, v := range values {
+		if v == "" {
+			res := make([]string, 0, len(values))
+			for _, v := range values {
+				if v != "" {
+					res = append(res, v)
+				}
+			}
+			return res
+		}
+	}
+	return values
 }
 
-// expandRouteConfig 展开路由配置
-func expandRouteConfig(config RouteConfig, module string) map[string][]RouteBuilder {
+// expandRouteConfig 展开路由配置，返回 map[方法名][]RouteBuilder 和配置错误（出错的路由被跳过）
+func expandRouteConfig(config RouteConfig, module string) (map[string][]RouteBuilder, []error) {
 	result := make(map[string][]RouteBuilder)
+	var errs []error
 
 	var global *RouteBuilder
 	if config.Global != nil {
@@ -357,17 +535,41 @@ func expandRouteConfig(config RouteConfig, module string) map[string][]RouteBuil
 	}
 
 	// 处理各个 HTTP 方法和 WebSocket
-	methods := []struct {
+	type methodBuilders struct {
 		name     string
 		builders []RouteBuilder
-	}{
+	}
+	methods := []methodBuilders{
 		{"GET", config.GET},
 		{"POST", config.POST},
 		{"PUT", config.PUT},
 		{"DELETE", config.DELETE},
+		{"PATCH", config.PATCH},
+		{"HEAD", config.HEAD},
+		{"OPTIONS", config.OPTIONS},
+		{"ANY", config.Any},
 		{"WS", config.WS}, // WebSocket 路由
 	}
 
+	// 自定义方法路由按声明的方法展开，每个方法一条
+	for _, builder := range config.Custom {
+		customMethods := builder.Methods
+		if builder.Method != "" {
+			customMethods = append([]string{builder.Method}, customMethods...)
+		}
+		if len(customMethods) == 0 {
+			errs = append(errs, &RouteError{
+				FuncName: extractFuncName(builder.FuncName),
+				Path:     builder.Path,
+				Err:      fmt.Errorf("%w: 自定义方法路由必须指定 Method 或 Methods", ErrInvalidRoute),
+			})
+			continue
+		}
+		for _, method := range customMethods {
+			methods = append(methods, methodBuilders{strings.ToUpper(strings.TrimSpace(method)), []RouteBuilder{builder}})
+		}
+	}
+
 	for _, m := range methods {
 		for _, builder := range m.builders {
 			// 合并全局配置
@@ -376,6 +578,7 @@ func expandRouteConfig(config RouteConfig, module string) map[string][]RouteBuil
 			}
 
 			builder.Method = m.name
+			builder.Methods = nil
 
 			// 构建完整路径
 			pathModel := ""
@@ -389,7 +592,11 @@ func expandRouteConfig(config RouteConfig, module string) map[string][]RouteBuil
 			// 提取函数名（支持字符串和函数引用两种形式）
 			funcName := extractFuncName(builder.FuncName)
 			if funcName == "" {
-				slog.Error("FuncName 不能为空", "method", m.name, "path", builder.Path)
+				errs = append(errs, &RouteError{
+					Method: m.name,
+					Path:   builder.Path,
+					Err:    fmt.Errorf("%w: FuncName 不能为空", ErrInvalidRoute),
+				})
 				continue
 			}
 
@@ -397,7 +604,7 @@ func expandRouteConfig(config RouteConfig, module string) map[string][]RouteBuil
 		}
 	}
 
-	return result
+	return result, errs
 }
 
 // mergeBuilder 合并全局配置和局部配置
@@ -419,6 +626,36 @@ func mergeBuilder(global, local RouteBuilder) RouteBuilder {
 		}
 	}
 
+	// 局部未配置时继承全局的 multipart 内存上限
+	if result.MaxMultipartMemory == 0 {
+		result.MaxMultipartMemory = global.MaxMultipartMemory
+	}
+
+	// 局部未配置时继承全局的绑定模式
+	if result.BindMode == BindModeDefault {
+		result.BindMode = global.BindMode
+	}
+	result.DisallowUnknown = result.DisallowUnknown || global.DisallowUnknown
+
+	// 局部未配置时继承全局的请求体大小上限
+	if result.MaxBodyBytes == 0 {
+		result.MaxBodyBytes = global.MaxBodyBytes
+	}
+
+	// 局部未配置时继承全局的版本信息（整个控制器属于同一个版本）
+	if result.Version == "" {
+		result.Version = global.Version
+	}
+	result.Deprecated = result.Deprecated || global.Deprecated
+	if result.Sunset.IsZero() {
+		result.Sunset = global.Sunset
+	}
+
+	// 局部未配置时继承全局的错误响应格式
+	if result.ErrorFormat == ErrorFormatDefault {
+		result.ErrorFormat = global.ErrorFormat
+	}
+
 	return result
 }
 
@@ -467,125 +704,112 @@ func buildParamBindings(methodType reflect.Method) ([]ParamBinding, error) {
 
 	params := make([]ParamBinding, 0, numParams-startIndex)
 	for i := startIndex; i < numParams; i++ {
-		paramType := methodType.Type.In(i)
-
-		isPtr := paramType.Kind() == reflect.Ptr
-		elemType := paramType
-		if isPtr {
-			elemType = paramType.Elem()
+		binding, err := newParamBinding(methodType.Type.In(i))
+		if err != nil {
+			return nil, err
 		}
-
-		// 构建默认值字段信息（传递原始参数类型，函数内部会处理）
-		defaultFields := buildDefaultFieldsWithIndex(paramType)
-
-		params = append(params, ParamBinding{
-			Params:        paramType,
-			ElemType:      elemType,
-			IsPtr:         isPtr,
-			DefaultFields: defaultFields,
-		})
+		params = append(params, binding)
 	}
 
 	return params, nil
 }
 
-// MountRoutes 挂载所有路由到 Echo 实例
-func MountRoutes(e *echo.Echo) {
-	routesMu.RLock()
-	defer routesMu.RUnlock()
+// newParamBinding 构建单个参数的绑定信息（默认值、query 字段、校验规则只在注册时计算一次）
+func newParamBinding(paramType reflect.Type) (ParamBinding, error) {
+	if isReaderParam(paramType) {
+		return ParamBinding{Params: paramType, ElemType: paramType, IsReader: true}, nil
+	}
+	if paramType == contextType {
+		return ParamBinding{Params: paramType, ElemType: paramType, IsContext: true}, nil
+	}
 
-	for _, route := range routes {
-		handler := buildHandler(route)
+	isPtr := paramType.Kind() == reflect.Ptr
+	elemType := paramType
+	if isPtr {
+		elemType = paramType.Elem()
+	}
 
-		finalPath := route.Path
-		if BasePrefixPath != "" && !route.NoUseBasePrefixPath {
-			finalPath = strings.TrimSuffix(BasePrefixPath, "/") + finalPath
-		}
+	// 构建默认值字段信息（传递原始参数类型，函数内部会处理）
+	defaultFields := buildDefaultFieldsWithIndex(paramType)
 
-		switch strings.ToUpper(route.Method) {
-		case "GET":
-			e.GET(finalPath, handler)
-		case "POST":
-			e.POST(finalPath, handler)
-		case "PUT":
-			e.PUT(finalPath, handler)
-		case "DELETE":
-			e.DELETE(finalPath, handler)
-		case "WS":
-			// WebSocket 使用 GET 方法注册，但在 handler 中检测升级
-			e.GET(finalPath, buildWebSocketHandler(route))
-		default:
-			slog.Error("不支持的 HTTP 方法", "method", route.Method)
-		}
+	// 编译校验规则
+	validator, err := buildStructValidator(elemType)
+	if err != nil {
+		return ParamBinding{}, fmt.Errorf("参数 %s: %w", paramType, err)
 	}
+
+	return ParamBinding{
+		Params:        paramType,
+		ElemType:      elemType,
+		IsPtr:         isPtr,
+		DefaultFields: defaultFields,
+		QueryFields:   buildQueryFieldsWithIndex(elemType),
+		SourceFields:  buildSourceFieldsWithIndex(elemType),
+		Body:          buildBodyBinding(elemType),
+		Validator:     validator,
+	}, nil
+}
+
+// Routes 默认实例实际挂载的路由（见 Server.Routes）
+func Routes() []RouteInfo {
+	return defaultServer.Routes()
+}
+
+// URLFor 按路由名生成默认实例的 URL（见 Server.URLFor）
+func URLFor(name string, params ...any) (string, error) {
+	return defaultServer.URLFor(name, params...)
+}
+
+// MountRoutes 挂载默认实例的所有路由到 Echo 实例，返回路由冲突（见 Server.MountRoutes）
+func MountRoutes(e *echo.Echo) error {
+	return defaultServer.MountRoutes(e)
 }
 
 // buildHandler 构建路由处理器（支持中间件）
 func buildHandler(route Route) echo.HandlerFunc {
+	if route.build != nil {
+		return withEnvelope(route.envelope, withErrorFormat(route.ErrorFormat, applyMiddlewares(route.build(route), route.Middlewares)))
+	}
+
 	params := route.Params
 	handlerFunc := route.Handler
 	paramsCount := len(params)
-	middlewares := route.Middlewares
+	binder := newRequestBinder(route)
+
+	// 返回值形式：无返回值、T、error 或 (T, error)
+	numOut := handlerFunc.Type().NumOut()
+	errorOnly := numOut == 1 && handlerFunc.Type().Out(0) == errorType
 
 	// 核心处理器
 	coreHandler := func(c echo.Context) error {
-		// 设置上下文参数
-		if route.CtxParams != nil {
-			for key, value := range route.CtxParams {
-				c.Set(key, value)
-			}
+		state, herr := binder.begin(c)
+		if herr != nil {
+			return writeBindError(c, herr)
 		}
 
-		// 构建调用参数
+		// 构建调用参数，第一个参数是 echo.Context
 		invokeArgs := make([]reflect.Value, 1, paramsCount+1)
-
-		ctx, _ := c.Get("context").(context.Context)
-		requestId := ctx.Value("requestId").(string)
-
-		// 第一个参数是 echo.Context
 		invokeArgs[0] = reflect.ValueOf(c)
 
 		// 绑定参数（支持多个参数）
-		// 先检查是否有参数需要 body，如果有则提前保存，避免多次读取导致 EOF
-		needBodyForAnyParam := false
-		for i := range params {
-			if hasJsonField(params[i].ElemType) {
-				needBodyForAnyParam = true
-				break
-			}
-		}
-
-		// 如果需要 body，提前保存
-		var bodyBytes []byte
-		if needBodyForAnyParam {
-			bodyBytes, _ = io.ReadAll(c.Request().Body)
-			c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		}
-
 		for i := range params {
 			paramBind := &params[i]
 
-			arg := reflect.New(paramBind.ElemType)
-
-			// 如果已保存 body，确保每次绑定前都能读取
-			if needBodyForAnyParam {
-				c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			// io.Reader 参数直接传入请求体（已受 MaxBodyBytes 限制）
+			if paramBind.IsReader {
+				invokeArgs = append(invokeArgs, reflect.ValueOf(c.Request().Body))
+				continue
 			}
 
-			// 使用精确绑定，根据字段标签分别从不同源绑定，避免冲突
-			if err := bindParamPrecise(c, arg.Interface(), paramBind.ElemType, bodyBytes); err != nil {
-				// 参数绑定失败，返回错误响应
-				return c.JSON(http.StatusBadRequest, BaseHttpError{
-					StatusCode: http.StatusBadRequest,
-					Code:       "INVALID_PARAM",
-					Message:    "参数绑定失败: " + err.Error(),
-					RequestId:  requestId,
-				}.GetResponse(requestId))
+			// context.Context 参数，客户端断开连接时取消
+			if paramBind.IsContext {
+				invokeArgs = append(invokeArgs, reflect.ValueOf(Context(c)))
+				continue
 			}
 
-			// 设置默认值（在绑定之后，这样默认值只会在字段为空时生效）
-			if len(paramBind.DefaultFields) > 0 {
-				fillParamWithDefaultOptimized(arg.Elem(), paramBind.DefaultFields)
+			arg := reflect.New(paramBind.ElemType)
+			if herr := binder.bind(c, state, paramBind, arg); herr != nil {
+				return writeBindError(c, herr)
 			}
 
 			if paramBind.IsPtr {
@@ -595,25 +819,219 @@ func buildHandler(route Route) echo.HandlerFunc {
 			}
 		}
 
+		if herr := state.validationError(); herr != nil {
+			return writeBindError(c, herr)
+		}
+
 		// 调用 handler
 		results := handlerFunc.Call(invokeArgs)
 
-		if len(results) > 0 && !results[0].IsNil() {
-			c.Set("Response", results[0].Interface())
+		var resp any
+		var err error
+		switch {
+		case numOut == 0:
+		case errorOnly:
+			err, _ = results[0].Interface().(error)
+		case numOut == 1:
+			resp = results[0].Interface()
+		default:
+			resp = results[0].Interface()
+			err, _ = results[1].Interface().(error)
 		}
-
-		return nil
+		return setHandlerResult(c, resp, err)
 	}
 
-	// 应用中间件（从后往前包装）
-	handler := coreHandler
+	return withEnvelope(route.envelope, withErrorFormat(route.ErrorFormat, applyMiddlewares(coreHandler, route.Middlewares)))
+}
+
+// applyMiddlewares 应用路由中间件（从后往前包装）
+func applyMiddlewares(handler echo.HandlerFunc, middlewares []echo.MiddlewareFunc) echo.HandlerFunc {
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		handler = middlewares[i](handler)
 	}
-
 	return handler
 }
 
+// requestBinder 请求参数绑定流程，反射 handler（buildHandler）与泛型 handler（Handle）共用
+// 绑定模式、未声明字段、请求体上限等配置在构建时计算一次
+type requestBinder struct {
+	params             []ParamBinding
+	ctxParams          map[string]string
+	bindMode           BindMode
+	knownBodyFields    bodyFieldSet
+	maxBodyBytes       int64
+	maxMultipartMemory int64
+}
+
+// bindState 单次请求的绑定状态
+type bindState struct {
+	requestId      string
+	bodyBytes      []byte
+	form           *requestForm
+	opts           bindOptions
+	validationErrs ValidationErrors // 所有参数的校验错误汇总后一次性返回
+}
+
+func newRequestBinder(route Route) *requestBinder {
+	b := &requestBinder{
+		params:             route.Params,
+		ctxParams:          route.CtxParams,
+		bindMode:           resolveBindMode(route.BindMode),
+		maxBodyBytes:       resolveMaxBodyBytes(route.MaxBodyBytes),
+		maxMultipartMemory: route.MaxMultipartMemory,
+	}
+	if route.DisallowUnknown || DefaultDisallowUnknownFields {
+		b.knownBodyFields = buildKnownBodyFields(route.Params)
+	}
+	return b
+}
+
+// begin 设置上下文参数，限制并读取请求体、解析表单，失败时返回需要响应的错误
+func (b *requestBinder) begin(c echo.Context) (*bindState, *BaseHttpError) {
+	// 设置上下文参数
+	for key, value := range b.ctxParams {
+		c.Set(key, value)
+	}
+
+	state := &bindState{requestId: RequestID(Context(c))}
+	requestId := state.requestId
+
+	// 限制请求体大小：声明了 Content-Length 的直接拒绝，其余在读取超出上限时拒绝
+	if req := c.Request(); b.maxBodyBytes > 0 && req.Body != nil {
+		if req.ContentLength > b.maxBodyBytes {
+			htperr := newBodyTooLargeError(b.maxBodyBytes, requestId)
+			return nil, &htperr
+		}
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, b.maxBodyBytes)
+	}
+
+	// 先检查是否有参数需要 body，如果有则提前保存，避免多次读取导致 EOF
+	// 表单请求的 body 交给表单解析，不提前整体读入内存（避免大文件上传占用内存）
+	needBodyForAnyParam := false
+	needFormForAnyParam := false
+	formRequest := isFormRequest(c.Request())
+	for i := range b.params {
+		if !formRequest && b.params[i].Body != nil {
+			needBodyForAnyParam = true
+		}
+		if formRequest && b.params[i].hasFormField() {
+			needFormForAnyParam = true
+		}
+	}
+
+	// 如果需要 body，提前保存
+	var codec Codec
+	if needBodyForAnyParam {
+		bodyBytes, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				htperr := newBodyTooLargeError(b.maxBodyBytes, requestId)
+				return nil, &htperr
+			}
+			return nil, newRequestError("INVALID_BODY", requestId, err.Error())
+		}
+		c.Request().Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		// 保存已读取的 body，日志中间件不需要再次读取
+		c.Set(requestBodyKey, bodyBytes)
+		state.bodyBytes = bodyBytes
+
+		// 按 Content-Type 选择解码器：严格模式拒绝未注册的 Content-Type，宽松模式按 JSON 尝试解码
+		if len(bodyBytes) > 0 {
+			var ok bool
+			if codec, ok = codecForContentType(c.Request().Header.Get(echo.HeaderContentType)); !ok {
+				if b.bindMode == BindModeStrict {
+					return nil, newRequestError("UNSUPPORTED_MEDIA_TYPE", requestId, c.Request().Header.Get(echo.HeaderContentType))
+				}
+				codec = JSONCodec{}
+			}
+		}
+	}
+
+	// 拒绝未声明的 body 字段（按所有参数的并集检查一次）
+	if b.knownBodyFields != nil && len(state.bodyBytes) > 0 {
+		if err := checkUnknownBodyFields(codec, state.bodyBytes, b.knownBodyFields); err != nil {
+			var bindErr *BindError
+			errors.As(err, &bindErr)
+			return nil, newBindError(requestId, bindErr)
+		}
+	}
+
+	// 如果需要表单，解析一次供所有参数使用
+	if needFormForAnyParam {
+		form, err := parseRequestForm(c.Request(), b.maxMultipartMemory)
+		if err != nil {
+			if isBodyTooLarge(err) {
+				htperr := newBodyTooLargeError(b.maxBodyBytes, requestId)
+				return nil, &htperr
+			}
+			return nil, newRequestError("INVALID_FORM", requestId, err.Error())
+		}
+		state.form = form
+	}
+
+	// body 绑定选项
+	state.opts = bindOptions{Strict: b.bindMode == BindModeStrict, Codec: codec}
+	return state, nil
+}
+
+// bind 绑定单个参数到 arg（指向 ElemType 的指针），并设置默认值、收集校验错误
+func (b *requestBinder) bind(c echo.Context, state *bindState, paramBind *ParamBinding, arg reflect.Value) *BaseHttpError {
+	// 使用精确绑定，根据字段标签分别从不同源绑定，避免冲突
+	if err := bindParamPrecise(c, arg.Interface(), paramBind, state.bodyBytes, state.form, state.opts); err != nil {
+		var bindErr *BindError
+		if errors.As(err, &bindErr) {
+			// 请求体格式或类型错误，返回字段和偏移信息
+			return newBindError(state.requestId, bindErr)
+		}
+		// 参数绑定失败，返回错误响应
+		return newRequestError("INVALID_PARAM", state.requestId, err.Error())
+	}
+
+	// 设置默认值（在绑定之后，这样默认值只会在字段为空时生效）
+	if len(paramBind.DefaultFields) > 0 {
+		fillParamWithDefaultOptimized(arg.Elem(), paramBind.DefaultFields)
+	}
+
+	// 校验参数（在默认值之后，默认值也需要满足规则）
+	if paramBind.Validator != nil {
+		state.validationErrs = append(state.validationErrs, paramBind.Validator.validate(arg.Elem(), "")...)
+	}
+	return nil
+}
+
+// validationError 所有参数的校验错误
+func (state *bindState) validationError() *BaseHttpError {
+	if len(state.validationErrs) == 0 {
+		return nil
+	}
+	htperr := newRequestError("VALIDATION_FAILED", state.requestId)
+	htperr.Details = state.validationErrs
+	return htperr
+}
+
+// newRequestError 绑定阶段按错误码创建的错误
+func newRequestError(code, requestId string, args ...any) *BaseHttpError {
+	htperr := NewError(code, args...)
+	htperr.RequestId = requestId
+	return &htperr
+}
+
+// newBindError 请求体绑定错误，BindError 作为 Details 返回，消息按 Accept-Language 翻译
+func newBindError(requestId string, bindErr *BindError) *BaseHttpError {
+	return &BaseHttpError{
+		StatusCode: http.StatusBadRequest,
+		Code:       "INVALID_BODY",
+		Message:    bindErr.Error(),
+		RequestId:  requestId,
+		Details:    bindErr,
+	}
+}
+
+// writeBindError 写出绑定阶段的错误响应
+func writeBindError(c echo.Context, herr *BaseHttpError) error {
+	return writeResponse(c, herr.StatusCode, responseBody(c, herr, herr.RequestId))
+}
+
 // buildWebSocketHandler 构建 WebSocket 处理器
 func buildWebSocketHandler(route Route) echo.HandlerFunc {
 	handlerFunc := route.Handler
@@ -635,10 +1053,7 @@ func buildWebSocketHandler(route Route) echo.HandlerFunc {
 		}
 
 		// 设置 context
-		if cx := c.Get("context"); cx != nil {
-			ctx := cx.(context.Context)
-			ctx = context.WithValue(ctx, "remote_ip", c.RealIP())
-		}
+		setContext(c, WithRemoteIP(Context(c), ClientIP(c)))
 
 		// 检测是否是 WebSocket 升级请求
 		conn, err := websocket.Accept(c.Response().Writer, c.Request(), &websocket.AcceptOptions{
@@ -0,0 +1,904 @@
+package echoApi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestContext(req *http.Request) echo.Context {
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+// bindTestParam 使用与 Register 相同的绑定信息绑定 dst
+func bindTestParam(t *testing.T, c echo.Context, dst any) error {
+	t.Helper()
+	binding, err := newParamBinding(reflect.TypeOf(dst))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bindParamPrecise(c, dst, &binding, nil, nil, bindOptions{})
+}
+
+type testCtrl struct{}
+
+type testCreateReq struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Page  int    `query:"page" binding:"min=1"`
+}
+
+func (t *testCtrl) Create(c echo.Context, req *testCreateReq) HttpResponse {
+	return BaseHttpResponse{Data: req}
+}
+
+// newTestEcho 将控制器方法挂载到独立的 Echo 实例，不影响全局路由
+func newTestEcho(t *testing.T, ctrl any, funcName, method, path string, opts ...func(*Route)) *echo.Echo {
+	t.Helper()
+	methodType, ok := reflect.TypeOf(ctrl).MethodByName(funcName)
+	if !ok {
+		t.Fatalf("method %s not found", funcName)
+	}
+	params, err := buildParamBindings(methodType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	route := Route{
+		Path:    path,
+		Method:  method,
+		Handler: reflect.ValueOf(ctrl).MethodByName(funcName),
+		Params:  params,
+	}
+	for _, opt := range opts {
+		opt(&route)
+	}
+
+	e := echo.New()
+	e.Use(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+	e.Add(method, path, buildHandler(route))
+	return e
+}
+
+func doTestRequest(e *echo.Echo, method, target string, body io.Reader, contentType string) (*httptest.ResponseRecorder, map[string]any) {
+	req := httptest.NewRequest(method, target, body)
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var res map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec, res
+}
+
+func TestBuildHandler_Validation(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Create", http.MethodPost, "/create")
+
+	rec, res := doTestRequest(e, http.MethodPost, "/create?page=0", strings.NewReader(`{"email":"bad"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+	details, _ := res["details"].([]any)
+	assert.Len(t, details, 3)
+
+	rec, res = doTestRequest(e, http.MethodPost, "/create?page=2", strings.NewReader(`{"name":"a","email":"a@b.cn"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotNil(t, res["data"])
+}
+
+type testUploadReq struct {
+	Title  string                  `form:"title" binding:"required"`
+	Tags   []string                `form:"tags"`
+	Avatar *multipart.FileHeader   `form:"avatar"`
+	Images []*multipart.FileHeader `form:"images"`
+}
+
+func (t *testCtrl) Upload(c echo.Context, req *testUploadReq) HttpResponse {
+	data := map[string]any{"title": req.Title, "tags": req.Tags, "images": len(req.Images)}
+	if req.Avatar != nil {
+		f, _ := req.Avatar.Open()
+		content, _ := io.ReadAll(f)
+		_ = f.Close()
+		data["avatar"] = string(content)
+	}
+	return BaseHttpResponse{Data: data}
+}
+
+func TestBuildHandler_UrlencodedForm(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Upload", http.MethodPost, "/upload")
+
+	rec, res := doTestRequest(e, http.MethodPost, "/upload", strings.NewReader("title=hi&tags=a&tags=b"), echo.MIMEApplicationForm)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{"title": "hi", "tags": []any{"a", "b"}, "images": float64(0)}, res["data"])
+}
+
+func TestBuildHandler_MultipartForm(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Upload", http.MethodPost, "/upload")
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("title", "hi")
+	fw, _ := mw.CreateFormFile("avatar", "a.txt")
+	_, _ = fw.Write([]byte("avatar-content"))
+	for _, name := range []string{"1.png", "2.png"} {
+		fw, _ = mw.CreateFormFile("images", name)
+		_, _ = fw.Write([]byte(name))
+	}
+	_ = mw.Close()
+
+	rec, res := doTestRequest(e, http.MethodPost, "/upload", &buf, mw.FormDataContentType())
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{"title": "hi", "tags": nil, "images": float64(2), "avatar": "avatar-content"}, res["data"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/upload", strings.NewReader("tags=a"), echo.MIMEApplicationForm)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+}
+
+func TestBindParamPrecise_Header(t *testing.T) {
+	type headerReq struct {
+		Token   string   `header:"x-auth-token"`
+		Retry   int      `header:"X-Retry"`
+		Debug   bool     `header:"x-debug"`
+		Accepts []string `header:"x-accept"`
+		Ids     []int64  `header:"x-ids"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Auth-Token", "tk")
+	req.Header.Set("x-retry", "3")
+	req.Header.Set("X-Debug", "true")
+	req.Header.Add("X-Accept", "a, b")
+	req.Header.Add("X-Accept", "c")
+	req.Header.Add("X-Ids", "1")
+	req.Header.Add("X-Ids", "9007199254740993")
+
+	var got headerReq
+	err := bindTestParam(t, newTestContext(req), &got)
+	assert.NoError(t, err)
+	assert.Equal(t, headerReq{
+		Token:   "tk",
+		Retry:   3,
+		Debug:   true,
+		Accepts: []string{"a", "b", "c"},
+		Ids:     []int64{1, 9007199254740993},
+	}, got)
+}
+
+func TestBindParamPrecise_DefaultHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("x-auth-token", "tk")
+	req.Header.Set("x-auth-version", "1.2.0")
+	req.Header.Set("Accept-Language", "zh-CN")
+
+	var got DefaultHeader
+	err := bindTestParam(t, newTestContext(req), &got)
+	assert.NoError(t, err)
+	assert.Equal(t, "tk", got.Token)
+	assert.Equal(t, "1.2.0", got.Version)
+	assert.Equal(t, "zh-CN", got.AcceptLanguage)
+}
+
+func TestBindParamPrecise_EmbeddedSources(t *testing.T) {
+	type SessionInfo struct {
+		Session string `cookie:"session"`
+	}
+	type testUpload struct {
+		Title string `form:"title"`
+	}
+	type embeddedReq struct {
+		DefaultHeader
+		*SessionInfo
+		testUpload
+		Name string `query:"name"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/?name=n", strings.NewReader("title=t"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set("x-auth-token", "tk")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	form, err := parseRequestForm(req, 0)
+	assert.NoError(t, err)
+	binding, err := newParamBinding(reflect.TypeOf(&embeddedReq{}))
+	assert.NoError(t, err)
+	assert.True(t, binding.hasFormField())
+
+	var got embeddedReq
+	assert.NoError(t, bindParamPrecise(newTestContext(req), &got, &binding, nil, form, bindOptions{}))
+	assert.Equal(t, "n", got.Name)
+	assert.Equal(t, "tk", got.Token)
+	assert.Equal(t, "t", got.Title)
+	if assert.NotNil(t, got.SessionInfo) {
+		assert.Equal(t, "s1", got.Session)
+	}
+
+	// 没有对应的值时不分配嵌入的结构体指针
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	got = embeddedReq{}
+	assert.NoError(t, bindParamPrecise(newTestContext(req), &got, &binding, nil, nil, bindOptions{}))
+	assert.Nil(t, got.SessionInfo)
+}
+
+func TestBindParamPrecise_HeaderConvertError(t *testing.T) {
+	type headerReq struct {
+		Retry int `header:"x-retry"`
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("x-retry", "abc")
+
+	var got headerReq
+	err := bindTestParam(t, newTestContext(req), &got)
+	assert.Error(t, err)
+}
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+func TestBindParamPrecise_QueryTypes(t *testing.T) {
+	type listReq struct {
+		Ids      []int64       `query:"ids"`
+		Tags     []string      `query:"tags,split"`
+		Raw      []string      `query:"raw"`
+		Page     *int          `query:"page"`
+		Size     *int          `query:"size"`
+		Since    time.Time     `query:"since"`
+		Day      time.Time     `query:"day" format:"2006-01-02"`
+		At       time.Time     `query:"at" format:"unix"`
+		Timeout  time.Duration `query:"timeout"`
+		Date     Date          `query:"date"`
+		DatePtr  *Date         `query:"date_ptr"`
+		IP       net.IP        `query:"ip"`
+		Level    testLevel     `query:"level"`
+		Levels   []testLevel   `query:"levels,split"`
+		Category string        `param:"category"`
+		Version  *int          `param:"version"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?ids=1&ids=9007199254740993&tags=a,b&tags=c&raw=a,b&size=20"+
+		"&since=2024-01-02T03:04:05Z&day=2024-05-06&at=1700000000&timeout=1m30s&date=2024-07-08&date_ptr=2024-07-09"+
+		"&ip=10.0.0.1&level=high&levels=low,high", nil)
+	c := newTestContext(req)
+	c.SetParamNames("category", "version")
+	c.SetParamValues("books", "3")
+
+	var got listReq
+	err := bindTestParam(t, c, &got)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 9007199254740993}, got.Ids)
+	assert.Equal(t, []string{"a", "b", "c"}, got.Tags)
+	assert.Equal(t, []string{"a,b"}, got.Raw)
+	assert.Nil(t, got.Page)
+	assert.Equal(t, 20, *got.Size)
+	assert.True(t, got.Since.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.Equal(t, "2024-05-06", got.Day.Format(time.DateOnly))
+	assert.Equal(t, int64(1700000000), got.At.Unix())
+	assert.Equal(t, 90*time.Second, got.Timeout)
+	assert.Equal(t, "2024-07-08", got.Date.ToString())
+	assert.Equal(t, "2024-07-09", got.DatePtr.ToString())
+	assert.Equal(t, "10.0.0.1", got.IP.String())
+	assert.Equal(t, testLevel(2), got.Level)
+	assert.Equal(t, []testLevel{1, 2}, got.Levels)
+	assert.Equal(t, "books", got.Category)
+	assert.Equal(t, 3, *got.Version)
+}
+
+func TestBindParamPrecise_QueryTypeErrors(t *testing.T) {
+	cases := map[string]any{
+		"/?v=300": &struct {
+			V int8 `query:"v"`
+		}{},
+		"/?v=1,x": &struct {
+			V []int `query:"v,split"`
+		}{},
+		"/?v=bad": &struct {
+			V time.Duration `query:"v"`
+		}{},
+		"/?v=mid": &struct {
+			V testLevel `query:"v"`
+		}{},
+		"/?v=2024": &struct {
+			V time.Time `query:"v"`
+		}{},
+		"/?v=x&v=1": &struct {
+			V []*int `query:"v"`
+		}{},
+	}
+	for target, dst := range cases {
+		c := newTestContext(httptest.NewRequest(http.MethodGet, target, nil))
+		err := bindTestParam(t, c, dst)
+		assert.Error(t, err, target)
+	}
+}
+
+type testPaging struct {
+	Page int `query:"page" default:"1"`
+	Size int `query:"size"`
+}
+
+type testSort struct {
+	Field string `query:"field"`
+	Desc  bool   `query:"desc"`
+}
+
+func TestBindParamPrecise_NestedQuery(t *testing.T) {
+	type listReq struct {
+		testPaging
+		Keyword string    `query:"q"`
+		Sort    *testSort `query:"sort"`
+		Range   struct {
+			From int `query:"from"`
+			To   int `query:"to"`
+		} `query:"range"`
+		Filter map[string]string `query:"filter"`
+		Multi  map[string][]int  `query:"multi"`
+		Empty  map[string]string `query:"empty"`
+		NoSort *testSort         `query:"nosort"`
+		Status []string          `query:"status"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?page=2&size=20&q=go&sort[field]=name&sort.desc=true"+
+		"&range[from]=1&range.to=9&filter[status]=open&filter.owner=me&multi[a]=1&multi[a]=2&status[]=a&status[]=b", nil)
+
+	var got listReq
+	err := bindTestParam(t, newTestContext(req), &got)
+	assert.NoError(t, err)
+	assert.Equal(t, testPaging{Page: 2, Size: 20}, got.testPaging)
+	assert.Equal(t, "go", got.Keyword)
+	assert.Equal(t, &testSort{Field: "name", Desc: true}, got.Sort)
+	assert.Equal(t, 1, got.Range.From)
+	assert.Equal(t, 9, got.Range.To)
+	assert.Equal(t, map[string]string{"status": "open", "owner": "me"}, got.Filter)
+	assert.Equal(t, map[string][]int{"a": {1, 2}}, got.Multi)
+	assert.Nil(t, got.Empty)
+	assert.Nil(t, got.NoSort)
+	assert.Equal(t, []string{"a", "b"}, got.Status)
+}
+
+func TestBindParamPrecise_Priority(t *testing.T) {
+	type priorityReq struct {
+		ID    int    `param:"id" query:"id" header:"x-id"`
+		Name  string `query:"name" header:"x-name"`
+		Token string `header:"x-token" form:"token"`
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/?id=2&name=query", strings.NewReader("token=form"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set("x-id", "3")
+	req.Header.Set("x-name", "header")
+	req.Header.Set("x-token", "header")
+	c := newTestContext(req)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	form, err := parseRequestForm(req, 0)
+	assert.NoError(t, err)
+	binding, err := newParamBinding(reflect.TypeOf(priorityReq{}))
+	assert.NoError(t, err)
+
+	var got priorityReq
+	assert.NoError(t, bindParamPrecise(c, &got, &binding, nil, form, bindOptions{}))
+	assert.Equal(t, priorityReq{ID: 1, Name: "query", Token: "header"}, got)
+}
+
+type testPriceReq struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+	Count int     `json:"count"`
+}
+
+func (t *testCtrl) Price(c echo.Context, req testPriceReq) HttpResponse {
+	return BaseHttpResponse{Data: req}
+}
+
+func TestBuildHandler_StrictBody(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Price", http.MethodPost, "/price")
+
+	rec, res := doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"name":"a",`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "INVALID_BODY", res["code"])
+	assert.Equal(t, float64(12), res["details"].(map[string]any)["offset"])
+
+	rec, res = doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"price":"9.9"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "price", res["details"].(map[string]any)["field"])
+
+	rec, _ = doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"count":1.5}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, res = doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"name":"a","price":9.9,"count":2,"extra":1}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{"name": "a", "price": 9.9, "count": float64(2)}, res["data"])
+}
+
+func TestBuildHandler_LenientAndUnknownFields(t *testing.T) {
+	lenient := newTestEcho(t, &testCtrl{}, "Price", http.MethodPost, "/price", func(r *Route) {
+		r.BindMode = BindModeLenient
+	})
+	rec, _ := doTestRequest(lenient, http.MethodPost, "/price", strings.NewReader(`{"name":"a",`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	strict := newTestEcho(t, &testCtrl{}, "Price", http.MethodPost, "/price", func(r *Route) {
+		r.DisallowUnknown = true
+	})
+	rec, res := doTestRequest(strict, http.MethodPost, "/price", strings.NewReader(`{"name":"a","extra":1}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "extra", res["details"].(map[string]any)["field"])
+
+	// 与 query 字段同名的 key 忽略，不算未声明
+	create := newTestEcho(t, &testCtrl{}, "Create", http.MethodPost, "/create", func(r *Route) {
+		r.DisallowUnknown = true
+	})
+	rec, res = doTestRequest(create, http.MethodPost, "/create?page=2", strings.NewReader(`{"name":"a","email":"a@b.cn","page":1}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(2), res["data"].(map[string]any)["Page"])
+}
+
+func TestCheckUnknownBodyFields(t *testing.T) {
+	binding, err := newParamBinding(reflect.TypeOf(&testOrderReq{}))
+	assert.NoError(t, err)
+	known := buildKnownBodyFields([]ParamBinding{binding})
+
+	check := func(body string) string {
+		err := checkUnknownBodyFields(JSONCodec{}, []byte(body), known)
+		if err == nil {
+			return ""
+		}
+		var bindErr *BindError
+		assert.ErrorAs(t, err, &bindErr)
+		return bindErr.Field
+	}
+	assert.Equal(t, "", check(`{"id":1,"source":"body","items":[{"skuId":1,"count":2}]}`))
+	// 嵌套对象逐层检查
+	assert.Equal(t, "items[1].price", check(`{"items":[{"skuId":1},{"skuId":2,"price":1}]}`))
+	// 没有 json 标签的字段不从 body 绑定
+	assert.Equal(t, "Extra", check(`{"Extra":{"a":1}}`))
+	// 不是对象的 body 不检查
+	assert.Equal(t, "", check(`[1,2]`))
+}
+
+func TestMergeBuilder_BindOptions(t *testing.T) {
+	global := RouteBuilder{BindMode: BindModeLenient, DisallowUnknown: true, MaxMultipartMemory: 1 << 10}
+	merged := mergeBuilder(global, RouteBuilder{})
+	assert.Equal(t, BindModeLenient, merged.BindMode)
+	assert.True(t, merged.DisallowUnknown)
+	assert.Equal(t, int64(1<<10), merged.MaxMultipartMemory)
+
+	merged = mergeBuilder(global, RouteBuilder{BindMode: BindModeStrict, MaxMultipartMemory: 1})
+	assert.Equal(t, BindModeStrict, merged.BindMode)
+	assert.Equal(t, int64(1), merged.MaxMultipartMemory)
+}
+
+type testOrderItem struct {
+	SkuID uint64 `json:"skuId"`
+	Count int    `json:"count"`
+}
+
+type testOrderReq struct {
+	ID      int64           `json:"id"`
+	UserID  *int64          `json:"userId"`
+	Items   []testOrderItem `json:"items"`
+	Extra   map[string]int64
+	Source  string `query:"source" json:"source"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type testCustomReq struct {
+	Raw string
+	ID  int64 `param:"id"`
+}
+
+func (r *testCustomReq) UnmarshalJSON(data []byte) error {
+	r.Raw = string(data)
+	return nil
+}
+
+func TestBuildBodyBinding(t *testing.T) {
+	direct := buildBodyBinding(reflect.TypeOf(testPriceReq{}))
+	assert.True(t, direct.Direct)
+
+	shadow := buildBodyBinding(reflect.TypeOf(testOrderReq{}))
+	assert.False(t, shadow.Direct)
+	assert.Equal(t, []string{"id", "userId", "items", "comment"}, shadow.Names)
+
+	custom := buildBodyBinding(reflect.TypeOf(testCustomReq{}))
+	assert.True(t, custom.Direct)
+
+	assert.Nil(t, buildBodyBinding(reflect.TypeOf(DefaultHeader{})))
+}
+
+func TestBindParamPrecise_BodyPrecision(t *testing.T) {
+	body := []byte(`{"id":9007199254740993,"userId":9223372036854775807,"source":"body","comment":"c",` +
+		`"items":[{"skuId":18446744073709551615,"count":2}],"Extra":{"a":1}}`)
+	req := httptest.NewRequest(http.MethodPost, "/?source=query", nil)
+	binding, err := newParamBinding(reflect.TypeOf(&testOrderReq{}))
+	assert.NoError(t, err)
+
+	var got testOrderReq
+	assert.NoError(t, bindParamPrecise(newTestContext(req), &got, &binding, body, nil, bindOptions{Strict: true}))
+	assert.Equal(t, int64(9007199254740993), got.ID)
+	assert.Equal(t, int64(9223372036854775807), *got.UserID)
+	assert.Equal(t, []testOrderItem{{SkuID: 18446744073709551615, Count: 2}}, got.Items)
+	assert.Equal(t, "query", got.Source)
+	assert.Equal(t, "c", got.Comment)
+	// 没有 json 标签的字段不从 body 绑定
+	assert.Nil(t, got.Extra)
+}
+
+func TestBindParamPrecise_CustomUnmarshal(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	c := newTestContext(req)
+	c.SetParamNames("id")
+	c.SetParamValues("42")
+	binding, err := newParamBinding(reflect.TypeOf(&testCustomReq{}))
+	assert.NoError(t, err)
+
+	var got testCustomReq
+	assert.NoError(t, bindParamPrecise(c, &got, &binding, []byte(`{"any":1}`), nil, bindOptions{Strict: true}))
+	assert.Equal(t, `{"any":1}`, got.Raw)
+	assert.Equal(t, int64(42), got.ID)
+}
+
+func TestBindParamPrecise_LenientKeepsValidFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	binding, err := newParamBinding(reflect.TypeOf(&testOrderReq{}))
+	assert.NoError(t, err)
+
+	var got testOrderReq
+	body := []byte(`{"id":"bad","comment":"ok"}`)
+	assert.Error(t, bindParamPrecise(newTestContext(req), &got, &binding, body, nil, bindOptions{Strict: true}))
+
+	got = testOrderReq{}
+	assert.NoError(t, bindParamPrecise(newTestContext(req), &got, &binding, body, nil, bindOptions{}))
+	assert.Equal(t, "ok", got.Comment)
+}
+
+func (t *testCtrl) Stream(c echo.Context, body io.Reader) HttpResponse {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return BaseHttpError{StatusCode: http.StatusRequestEntityTooLarge, Message: err.Error()}
+	}
+	return BaseHttpResponse{Data: len(data)}
+}
+
+func TestBuildHandler_MaxBodyBytes(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Price", http.MethodPost, "/price", func(r *Route) {
+		r.MaxBodyBytes = 16
+	})
+
+	rec, res := doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"name":"a"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 声明了 Content-Length
+	rec, res = doTestRequest(e, http.MethodPost, "/price", strings.NewReader(`{"name":"aaaaaaaaaaaaaaaa"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "BODY_TOO_LARGE", res["code"])
+
+	// 未声明 Content-Length（chunked）
+	req := httptest.NewRequest(http.MethodPost, "/price", io.MultiReader(strings.NewReader(`{"name":"aaaaaaaaaaaaaaaa"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// 表单请求同样受限制
+	e = newTestEcho(t, &testCtrl{}, "Upload", http.MethodPost, "/upload", func(r *Route) {
+		r.MaxBodyBytes = 16
+	})
+	req = httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("title=aaaaaaaaaaaaaaaaaaaa"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBuildHandler_ReaderParam(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Stream", http.MethodPost, "/stream", func(r *Route) {
+		r.MaxBodyBytes = 64
+	})
+
+	rec, res := doTestRequest(e, http.MethodPost, "/stream", strings.NewReader(strings.Repeat("a", 64)), echo.MIMEOctetStream)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(64), res["data"])
+
+	// 流式读取超出上限
+	req := httptest.NewRequest(http.MethodPost, "/stream", strings.NewReader(strings.Repeat("a", 100)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	req.ContentLength = -1
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), "request body too large")
+}
+
+func TestMergeBuilder_MaxBodyBytes(t *testing.T) {
+	res := mergeBuilder(RouteBuilder{MaxBodyBytes: 1 << 10}, RouteBuilder{})
+	assert.Equal(t, int64(1<<10), res.MaxBodyBytes)
+
+	res = mergeBuilder(RouteBuilder{MaxBodyBytes: 1 << 10}, RouteBuilder{MaxBodyBytes: -1})
+	assert.Equal(t, int64(0), resolveMaxBodyBytes(res.MaxBodyBytes))
+	assert.Equal(t, DefaultMaxBodyBytes, resolveMaxBodyBytes(0))
+}
+
+func TestGetRequestParamsEcho_LimitBody(t *testing.T) {
+	old := MaxLogBodyBytes
+	MaxLogBodyBytes = 8
+	defer func() { MaxLogBodyBytes = old }()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"abcdefg"}`))
+	c := newTestContext(req)
+	params := GetRequestParamsEcho(c)
+	assert.Equal(t, LogStr(`{"name":...(truncated)`), params.Body)
+
+	// 后续读取仍然拿到完整的 body
+	body, _ := io.ReadAll(c.Request().Body)
+	assert.Equal(t, `{"name":"abcdefg"}`, string(body))
+
+	data, _ := json.Marshal(params.Body)
+	assert.Equal(t, `"{\"name\":...(truncated)"`, string(data))
+}
+
+type testSessionReq struct {
+	Session string    `cookie:"session" binding:"required"`
+	Theme   string    `cookie:"theme" header:"X-Theme"`
+	Ids     []int64   `cookie:"ids,split"`
+	Seen    time.Time `cookie:"seen" format:"unix"`
+}
+
+func (t *testCtrl) Session(c echo.Context, req *testSessionReq) HttpResponse {
+	return BaseHttpResponse{
+		Data: req,
+		Cookies: []Cookie{
+			{Name: "session", Value: req.Session + "-new", Path: "/", MaxAge: 3600, SameSite: http.SameSiteLaxMode, Secure: true, HttpOnly: true},
+			{Name: "theme", MaxAge: -1},
+		},
+	}
+}
+
+func TestBindParamPrecise_Cookie(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "ids", Value: "1,2"})
+	req.AddCookie(&http.Cookie{Name: "seen", Value: "1700000000"})
+
+	var got testSessionReq
+	bindTestParam(t, newTestContext(req), &got)
+	assert.Equal(t, "s1", got.Session)
+	assert.Equal(t, "dark", got.Theme)
+	assert.Equal(t, []int64{1, 2}, got.Ids)
+	assert.Equal(t, int64(1700000000), got.Seen.Unix())
+
+	// header 优先于 cookie
+	req.Header.Set("X-Theme", "light")
+	got = testSessionReq{}
+	bindTestParam(t, newTestContext(req), &got)
+	assert.Equal(t, "light", got.Theme)
+
+	// 类型转换错误
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "ids", Value: "a"})
+	binding, err := newParamBinding(reflect.TypeOf(&testSessionReq{}))
+	assert.NoError(t, err)
+	assert.Error(t, bindParamPrecise(newTestContext(req), &testSessionReq{}, &binding, nil, nil, bindOptions{}))
+}
+
+func TestBuildHandler_ResponseCookies(t *testing.T) {
+	e := newTestEcho(t, &testCtrl{}, "Session", http.MethodGet, "/session")
+
+	rec, res := doTestRequest(e, http.MethodGet, "/session", nil, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "VALIDATION_FAILED", res["code"])
+	assert.Empty(t, rec.Result().Cookies())
+
+	req := httptest.NewRequest(http.MethodGet, "/session", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 2)
+	assert.Equal(t, "session=s1-new; Path=/; Max-Age=3600; HttpOnly; Secure; SameSite=Lax", cookies[0].String())
+	assert.Equal(t, "theme", cookies[1].Name)
+	assert.Equal(t, -1, cookies[1].MaxAge)
+}
+
+type testMethodCtrl struct{}
+
+func (t *testMethodCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		PATCH:   []RouteBuilder{{Path: "/item", FuncName: "Name"}},
+		HEAD:    []RouteBuilder{{Path: "/item", FuncName: "Name"}},
+		OPTIONS: []RouteBuilder{{Path: "/item", FuncName: "Name"}},
+		Any:     []RouteBuilder{{Path: "/any", FuncName: "Name"}},
+		Custom: []RouteBuilder{
+			{Method: "propfind", Path: "/dav", FuncName: "Name"},
+			{Methods: []string{"GET", "POST"}, Path: "/multi", FuncName: "Name"},
+		},
+	}
+}
+
+func (t *testMethodCtrl) Name(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: c.Request().Method}
+}
+
+func TestExpandRouteConfig_Methods(t *testing.T) {
+	routes, errs := expandRouteConfig((&testMethodCtrl{}).RouteConfig(), "")
+	assert.Empty(t, errs)
+	builders := routes["Name"]
+
+	var got []string
+	for _, b := range builders {
+		got = append(got, b.Method+" "+b.Path)
+		assert.Nil(t, b.Methods)
+	}
+	assert.Equal(t, []string{
+		"PATCH /item", "HEAD /item", "OPTIONS /item", "ANY /any",
+		"PROPFIND /dav", "GET /multi", "POST /multi",
+	}, got)
+}
+
+func TestMountRoutes_Methods(t *testing.T) {
+	srv := NewServer("/api")
+	assert.NoError(t, srv.Register(&testMethodCtrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	cases := []struct {
+		method, path string
+		code         int
+	}{
+		{http.MethodPatch, "/api/item", http.StatusOK},
+		{http.MethodHead, "/api/item", http.StatusOK},
+		{http.MethodOptions, "/api/item", http.StatusOK},
+		{http.MethodGet, "/api/item", http.StatusMethodNotAllowed},
+		{http.MethodDelete, "/api/any", http.StatusOK},
+		{"PROPFIND", "/api/dav", http.StatusOK},
+		{http.MethodPost, "/api/multi", http.StatusOK},
+		{http.MethodPut, "/api/multi", http.StatusMethodNotAllowed},
+	}
+	for _, tc := range cases {
+		rec, res := doTestRequest(e, tc.method, tc.path, nil, "")
+		assert.Equal(t, tc.code, rec.Code, tc.method+" "+tc.path)
+		if tc.code == http.StatusOK && tc.method != http.MethodHead {
+			assert.Equal(t, tc.method, res["data"])
+		}
+	}
+}
+
+func TestCorsMiddleware_Options(t *testing.T) {
+	e := echo.New()
+	e.Use(CorsMiddleware(DefaultCorsConfig()))
+	e.OPTIONS("/item", func(c echo.Context) error {
+		return c.String(http.StatusOK, "handler")
+	})
+
+	// 预检请求由中间件直接返回
+	req := httptest.NewRequest(http.MethodOptions, "/item", nil)
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPatch)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec, _ = doTestRequest(e, http.MethodOptions, "/item", nil, "")
+	assert.Equal(t, "handler", rec.Body.String())
+}
+
+type testResultCtrl struct{}
+
+func (t *testResultCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/struct", FuncName: "Struct"},
+			{Path: "/pair", FuncName: "Pair"},
+			{Path: "/err", FuncName: "Err"},
+			{Path: "/none", FuncName: "None"},
+			{Path: "/map", FuncName: "Map"},
+			{Path: "/list", FuncName: "List"},
+		},
+	}
+}
+
+func (t *testResultCtrl) Struct(c echo.Context) testTypedResp {
+	return testTypedResp{Name: "s"}
+}
+
+func (t *testResultCtrl) Pair(c echo.Context, req *testPageReq) (*testTypedResp, error) {
+	if req.Page == 0 {
+		return nil, BaseHttpError{StatusCode: http.StatusNotFound, Code: "NOT_FOUND", Message: "not found"}
+	}
+	return &testTypedResp{Name: "p", Page: req.Page}, nil
+}
+
+func (t *testResultCtrl) Err(c echo.Context, req *testPageReq) error {
+	if req.Page == 0 {
+		return fmt.Errorf("wrap: %w", BaseHttpError{StatusCode: http.StatusConflict, Code: "CONFLICT", Message: "conflict"})
+	}
+	if req.Page == 1 {
+		return errors.New("fail")
+	}
+	return nil
+}
+
+func (t *testResultCtrl) None(c echo.Context) {
+	c.Set("Response", BaseHttpResponse{Data: "none"})
+}
+
+func (t *testResultCtrl) Map(c echo.Context) map[string]any {
+	return nil
+}
+
+func (t *testResultCtrl) List(c echo.Context) ([]testTypedResp, error) {
+	return nil, nil
+}
+
+type testPageReq struct {
+	Page int `query:"page"`
+}
+
+func TestBuildHandler_Results(t *testing.T) {
+	srv := NewServer("")
+	assert.NoError(t, srv.Register(&testResultCtrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	cases := []struct {
+		target string
+		code   int
+		data   any
+		errc   string
+	}{
+		{"/struct", http.StatusOK, map[string]any{"name": "s", "page": float64(0)}, ""},
+		{"/pair?page=3", http.StatusOK, map[string]any{"name": "p", "page": float64(3)}, ""},
+		{"/pair", http.StatusNotFound, nil, "NOT_FOUND"},
+		{"/err", http.StatusConflict, nil, "CONFLICT"},
+		{"/err?page=1", http.StatusInternalServerError, nil, "INTERNAL_ERROR"},
+		{"/none", http.StatusOK, "none", ""},
+	}
+	for _, tc := range cases {
+		rec, res := doTestRequest(e, http.MethodGet, tc.target, nil, "")
+		assert.Equal(t, tc.code, rec.Code, tc.target)
+		assert.Equal(t, tc.data, res["data"], tc.target)
+		if tc.errc != "" {
+			assert.Equal(t, tc.errc, res["code"], tc.target)
+		}
+	}
+
+	// 有类型的 nil 按 data: null 响应
+	for _, target := range []string{"/map", "/list"} {
+		rec, res := doTestRequest(e, http.MethodGet, target, nil, "")
+		assert.Equal(t, http.StatusOK, rec.Code, target)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON, target)
+		assert.Contains(t, res, "data", target)
+		assert.Nil(t, res["data"], target)
+	}
+
+	// 只返回 error 且为 nil 时不设置响应（handler 自己写出了响应）
+	rec, _ := doTestRequest(e, http.MethodGet, "/err?page=2", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
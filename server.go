@@ -0,0 +1,376 @@
+package echoApi
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Server 路由注册表，拥有自己的控制器、路由前缀、中间件和配置
+// 同一进程中可以创建多个互不影响的实例（如 admin 和 public 两个服务），零值可直接使用
+// 包级的 Register、MountRoutes、NewEcho 操作默认实例
+type Server struct {
+	BasePrefixPath     string                // 路由前缀，如 /api（默认实例为空时使用包级的 BasePrefixPath）
+	Middlewares        []echo.MiddlewareFunc // NewEcho 时挂载到 Echo 上的中间件（在 NewEcho 参数的中间件之前）
+	BindMode           BindMode              // 路由未配置时使用的绑定模式，BindModeDefault 表示使用 DefaultBindMode
+	DisallowUnknown    bool                  // 所有路由都拒绝 body 中未声明的字段
+	MaxBodyBytes       int64                 // 路由未配置时使用的请求体大小上限，0 表示使用 DefaultMaxBodyBytes
+	MaxMultipartMemory int64                 // 路由未配置时使用的 multipart 内存上限，0 表示使用 DefaultMaxMultipartMemory
+	Versioning         *VersionConfig        // API 版本选择方式，nil 表示使用 DefaultVersionConfig
+	Strict             bool                  // 严格模式：控制器有任何路由错误时整体不注册，挂载时发现冲突不挂载任何路由（NewEcho 直接 panic）
+	RouteList          *RouteListConfig      // 路由列表调试接口，nil 表示不开启
+	Envelope           *Envelope             // 响应信封格式，nil 表示使用 DefaultEnvelope
+
+	routes []Route
+	mu     sync.RWMutex
+}
+
+// defaultServer 默认实例，未设置 BasePrefixPath 时使用包级的 BasePrefixPath
+var defaultServer = &Server{}
+
+// basePrefixPath 实例的路由前缀
+// 默认实例的 BasePrefixPath 为空时使用包级的 BasePrefixPath（默认实例需要去掉前缀时将包级变量设为空）
+func (s *Server) basePrefixPath() string {
+	if s == defaultServer && s.BasePrefixPath == "" {
+		return BasePrefixPath
+	}
+	return s.BasePrefixPath
+}
+
+// NewServer 创建实例，路由前缀为 prefix（可以为空）
+func NewServer(prefix string) *Server {
+	return &Server{BasePrefixPath: prefix}
+}
+
+// DefaultServer 返回包级函数使用的默认实例
+func DefaultServer() *Server {
+	return defaultServer
+}
+
+// Register 注册控制器（类型安全版本）
+func (s *Server) Register(ctrl Controller) error {
+	return s.register(ctrl, nil)
+}
+
+// register 注册控制器，group 不为 nil 时路由挂载到分组下
+// 检查未知的 FuncName、handler 签名、版本号以及与已注册路由的冲突，所有问题汇总后返回
+// 非严格模式下有问题的路由被跳过，其余路由照常注册；严格模式下整个控制器都不注册
+func (s *Server) register(ctrl Controller, group *Group) error {
+	ctrlType := reflect.TypeOf(ctrl)
+	if ctrlType.Kind() != reflect.Ptr {
+		return fmt.Errorf("controller must be a pointer, got %T", ctrl)
+	}
+
+	ctrlValue := reflect.ValueOf(ctrl)
+	module := getModuleName(ctrlType)
+
+	config := ctrl.RouteConfig()
+
+	// 将路由扁平化为 map[方法名][]RouteBuilder
+	pathMap, errs := expandRouteConfig(config, strings.ToLower(module))
+	for _, err := range errs {
+		err.(*RouteError).Controller = module
+	}
+
+	// 遍历控制器方法
+	var newRoutes []Route
+	methodCount := ctrlType.NumMethod()
+	for i := 0; i < methodCount; i++ {
+		method := ctrlValue.Method(i)
+		methodType := ctrlType.Method(i)
+		actionName := methodType.Name
+
+		builders, exists := pathMap[actionName]
+		if !exists {
+			continue
+		}
+		delete(pathMap, actionName)
+
+		for _, builder := range builders {
+			newRouteError := func(err error) error {
+				return &RouteError{Controller: module, FuncName: actionName, Method: builder.Method, Path: builder.Path, Err: err}
+			}
+
+			if err := checkBuilderVersion(builder); err != nil {
+				errs = append(errs, newRouteError(err))
+				continue
+			}
+			if err := checkHandlerSignature(builder.Method, methodType.Type); err != nil {
+				errs = append(errs, newRouteError(err))
+				continue
+			}
+
+			// WebSocket handler 不做参数绑定
+			var params []ParamBinding
+			if builder.Method != "WS" {
+				var err error
+				if params, err = buildParamBindings(methodType); err != nil {
+					errs = append(errs, newRouteError(fmt.Errorf("%w: 构建参数绑定失败: %w", ErrInvalidRoute, err)))
+					continue
+				}
+			}
+
+			route := newRoute(builder)
+			route.Handler = method
+			route.Params = params
+			route.Controller = module
+			route.FuncName = actionName
+			newRoutes = append(newRoutes, route)
+		}
+	}
+
+	// 剩下的都是控制器中不存在的方法
+	unknown := make([]string, 0, len(pathMap))
+	for funcName := range pathMap {
+		unknown = append(unknown, funcName)
+	}
+	sort.Strings(unknown)
+	for _, funcName := range unknown {
+		for _, builder := range pathMap[funcName] {
+			errs = append(errs, &RouteError{Controller: module, FuncName: funcName, Method: builder.Method, Path: builder.Path, Err: ErrUnknownFunc})
+		}
+	}
+
+	return s.addRoutes(newRoutes, group, errs)
+}
+
+// newRoute 由展开后的 RouteBuilder 创建路由（Handler、Params 等由调用方设置）
+func newRoute(builder RouteBuilder) Route {
+	return Route{
+		Path:                builder.Path,
+		Method:              builder.Method,
+		Middlewares:         builder.Middlewares,
+		NoUseBasePrefixPath: builder.NoUseBasePrefixPath,
+		CtxParams:           builder.CtxParams,
+		MaxMultipartMemory:  builder.MaxMultipartMemory,
+		BindMode:            builder.BindMode,
+		DisallowUnknown:     builder.DisallowUnknown,
+		MaxBodyBytes:        builder.MaxBodyBytes,
+		Version:             builder.Version,
+		Deprecated:          builder.Deprecated,
+		Sunset:              builder.Sunset,
+		Name:                builder.Name,
+		ErrorFormat:         builder.ErrorFormat,
+	}
+}
+
+// checkBuilderVersion 检查版本号格式
+func checkBuilderVersion(builder RouteBuilder) error {
+	if builder.Version == "" {
+		return nil
+	}
+	if _, ok := parseVersion(builder.Version); !ok {
+		return fmt.Errorf("%w: 版本号 %q 格式错误", ErrInvalidRoute, builder.Version)
+	}
+	return nil
+}
+
+// addRoutes 应用分组和实例配置后添加路由，errs 为调用方已发现的问题
+// 与已注册的路由以及本次的其他路由比较冲突，所有问题汇总后返回；严格模式下有任何问题时不添加
+func (s *Server) addRoutes(newRoutes []Route, group *Group, errs []error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 前缀在挂载时才确定，只比较前缀配置相同的路由
+	accepted := make([]Route, 0, len(newRoutes))
+	for _, route := range newRoutes {
+		group.apply(&route)
+		s.applyDefaults(&route)
+
+		err := findRouteConflict(s.versioning(), route, s.routes, accepted)
+		if err == nil {
+			err = findRouteNameConflict(route, s.routes, accepted)
+		}
+		if err != nil {
+			errs = append(errs, &RouteError{Controller: route.Controller, FuncName: route.FuncName, Method: route.Method, Path: route.Path, Err: err})
+			continue
+		}
+		accepted = append(accepted, route)
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			slog.Error("路由注册失败", "error", err)
+		}
+		if s.Strict {
+			return errors.Join(errs...)
+		}
+	}
+	s.routes = append(s.routes, accepted...)
+	return errors.Join(errs...)
+}
+
+// routeBasePath 路由实际使用的前缀
+func routeBasePath(prefix string, route Route) string {
+	if prefix == "" || route.NoUseBasePrefixPath {
+		return ""
+	}
+	return strings.TrimSuffix(prefix, "/")
+}
+
+// findRouteConflict 在已有路由中查找与 route 冲突的路由
+func findRouteConflict(config VersionConfig, route Route, routeLists ...[]Route) error {
+	for _, routes := range routeLists {
+		for _, other := range routes {
+			if other.NoUseBasePrefixPath != route.NoUseBasePrefixPath {
+				continue
+			}
+			if err := checkMountedConflict(config, route, other, "", ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyDefaults 路由未配置的项使用实例的配置
+func (s *Server) applyDefaults(route *Route) {
+	if route.BindMode == BindModeDefault {
+		route.BindMode = s.BindMode
+	}
+	route.DisallowUnknown = route.DisallowUnknown || s.DisallowUnknown
+	if route.MaxBodyBytes == 0 {
+		route.MaxBodyBytes = s.MaxBodyBytes
+	}
+	if route.MaxMultipartMemory == 0 {
+		route.MaxMultipartMemory = s.MaxMultipartMemory
+	}
+	route.envelope = s.Envelope
+}
+
+// MountRoutes 挂载实例的所有路由到 Echo 实例
+// 加上路由前缀后再次检查冲突：非严格模式下跳过冲突的路由（先注册的生效），严格模式下不挂载任何路由
+func (s *Server) MountRoutes(e *echo.Echo) error {
+	return s.mountRoutes(e, s.basePrefixPath())
+}
+
+func (s *Server) mountRoutes(e *echo.Echo, prefix string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mounted, err := s.resolveRoutes(prefix)
+	if err != nil {
+		for _, routeErr := range err.(interface{ Unwrap() []error }).Unwrap() {
+			slog.Error("路由挂载失败", "error", routeErr)
+		}
+		if s.Strict {
+			return err
+		}
+	}
+
+	versioning := s.versioning()
+	// 同一方法和路径的多个版本合并为一个按版本分发的 handler
+	dispatchers := make(map[string]*versionDispatcher)
+	var dispatcherKeys []string
+
+	for _, m := range mounted {
+		route, basePath := m.Route, m.basePath
+		finalPath := basePath + route.Path
+		method := strings.ToUpper(route.Method)
+
+		if route.Version == "" || method == "WS" {
+			mountRoute(e, method, finalPath, route)
+			continue
+		}
+
+		handler := withVersionHeaders(route, buildHandler(route))
+		key := method + " " + finalPath
+		d, ok := dispatchers[key]
+		if !ok {
+			d = &versionDispatcher{method: method, path: finalPath, config: versioning}
+			dispatchers[key] = d
+			dispatcherKeys = append(dispatcherKeys, key)
+		}
+		d.add(route.Version, handler)
+
+		// 路径版本：/api/v2/user/info 直接对应 v2，不做兼容回退
+		if versionPath := versioning.versionPath(basePath, route); versionPath != "" {
+			addRoute(e, method, versionPath, handler)
+		}
+	}
+
+	for _, key := range dispatcherKeys {
+		d := dispatchers[key]
+		addRoute(e, d.method, d.path, withEnvelope(s.Envelope, d.handle))
+	}
+
+	if s.RouteList != nil {
+		s.mountRouteList(e, *s.RouteList, prefix)
+	}
+	return err
+}
+
+// mountedRoute 加上路由前缀后实际挂载的路由
+type mountedRoute struct {
+	Route
+	basePath string
+}
+
+// resolveRoutes 计算路由加上前缀后的最终路径，并检查冲突（调用方需持有读锁）
+// 冲突的路由被排除（先注册的生效），冲突通过 errors.Join 汇总返回
+func (s *Server) resolveRoutes(prefix string) ([]mountedRoute, error) {
+	mounted := make([]mountedRoute, 0, len(s.routes))
+	versioning := s.versioning()
+	var errs []error
+	for _, route := range s.routes {
+		basePath := routeBasePath(prefix, route)
+
+		var conflict error
+		for _, other := range mounted {
+			if conflict = checkMountedConflict(versioning, route, other.Route, basePath, other.basePath); conflict != nil {
+				break
+			}
+		}
+		if conflict != nil {
+			errs = append(errs, &RouteError{Controller: route.Controller, FuncName: route.FuncName, Method: route.Method, Path: basePath + route.Path, Err: conflict})
+			continue
+		}
+		mounted = append(mounted, mountedRoute{Route: route, basePath: basePath})
+	}
+	return mounted, errors.Join(errs...)
+}
+
+// mountRoute 挂载单个路由
+func mountRoute(e *echo.Echo, method, path string, route Route) {
+	if method == "WS" {
+		// WebSocket 使用 GET 方法注册，但在 handler 中检测升级
+		e.GET(path, buildWebSocketHandler(route))
+		return
+	}
+	addRoute(e, method, path, withVersionHeaders(route, buildHandler(route)))
+}
+
+// addRoute 按方法注册 handler
+func addRoute(e *echo.Echo, method, path string, handler echo.HandlerFunc) {
+	switch method {
+	case "ANY":
+		e.Any(path, handler)
+	case "":
+		slog.Error("不支持的 HTTP 方法", "method", method, "path", path)
+	default:
+		// GET、POST、PATCH、HEAD、OPTIONS 以及自定义方法
+		e.Add(method, path, handler)
+	}
+}
+
+// NewEcho 创建 Echo 实例并挂载实例的所有路由
+func (s *Server) NewEcho(middlewares ...echo.MiddlewareFunc) *echo.Echo {
+	r := echo.New()
+	// c.RealIP() 与 ClientIP 使用相同的受信任代理规则
+	r.IPExtractor = ResolveClientIP
+	baseMiddleWares := append([]echo.MiddlewareFunc{}, s.Middlewares...)
+	if len(middlewares) > 0 {
+		baseMiddleWares = append(baseMiddleWares, middlewares...)
+	}
+	r.Use(baseMiddleWares...)
+	if err := s.MountRoutes(r); err != nil && s.Strict {
+		panic(err)
+	}
+	return r
+}
@@ -0,0 +1,100 @@
+package echoApi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAdminCtrl struct{}
+
+func (t *testAdminCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		POST: []RouteBuilder{{Path: "/price", FuncName: "Price"}},
+	}
+}
+
+func (t *testAdminCtrl) Price(c echo.Context, req testPriceReq) HttpResponse {
+	return BaseHttpResponse{Data: req}
+}
+
+func TestServer_Isolation(t *testing.T) {
+	public := NewServer("/api")
+	admin := &Server{BasePrefixPath: "/admin", MaxBodyBytes: 12, BindMode: BindModeLenient}
+	assert.NoError(t, public.Register(&testMethodCtrl{}))
+	assert.NoError(t, admin.Register(&testAdminCtrl{}))
+
+	mws := []echo.MiddlewareFunc{BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil)}
+	publicEcho := public.NewEcho(mws...)
+	adminEcho := admin.NewEcho(mws...)
+
+	rec, _ := doTestRequest(publicEcho, http.MethodPatch, "/api/item", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec, _ = doTestRequest(adminEcho, http.MethodPatch, "/api/item", nil, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec, _ = doTestRequest(publicEcho, http.MethodPost, "/admin/price", strings.NewReader("{}"), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 实例配置作为路由的默认值
+	rec, _ = doTestRequest(adminEcho, http.MethodPost, "/admin/price", strings.NewReader(`{"count":"x"}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	rec, _ = doTestRequest(adminEcho, http.MethodPost, "/admin/price", strings.NewReader(`{"name":1}`), echo.MIMEApplicationJSON)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// 默认实例不受影响
+	for _, r := range DefaultServer().routes {
+		assert.NotEqual(t, "/price", r.Path)
+	}
+}
+
+type testDefaultCtrl struct{}
+
+func (t *testDefaultCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{{Path: "/default/item", FuncName: "Item", Name: "testDefaultItem"}},
+	}
+}
+
+func (t *testDefaultCtrl) Item(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: c.Get("default")}
+}
+
+func TestDefaultServer(t *testing.T) {
+	assert.NoError(t, Register(&testDefaultCtrl{}))
+	srv := DefaultServer()
+	srv.Middlewares = []echo.MiddlewareFunc{func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("default", "mw")
+			return next(c)
+		}
+	}}
+	t.Cleanup(func() { srv.Middlewares = nil })
+
+	// 包级函数与默认实例的方法使用同一个路由前缀和中间件
+	mws := []echo.MiddlewareFunc{BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil)}
+	for _, e := range []*echo.Echo{NewEcho(mws...), srv.NewEcho(mws...)} {
+		rec, res := doTestRequest(e, http.MethodGet, "/api/default/item", nil, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "mw", res["data"])
+	}
+
+	url, err := srv.URLFor("testDefaultItem")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/default/item", url)
+	url, err = URLFor("testDefaultItem")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/default/item", url)
+	assert.Equal(t, Routes(), srv.Routes())
+
+	// 默认实例设置了 BasePrefixPath 时以其为准
+	srv.BasePrefixPath = "/v"
+	t.Cleanup(func() { srv.BasePrefixPath = "" })
+	rec, _ := doTestRequest(NewEcho(mws...), http.MethodGet, "/v/default/item", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	url, err = URLFor("testDefaultItem")
+	assert.NoError(t, err)
+	assert.Equal(t, "/v/default/item", url)
+}
@@ -13,17 +13,7 @@ type EchoConfig struct {
 	HideServerMiddleLogHeaders bool   `json:"hideServerMiddleLogHeaders"` // 是否隐藏内置中间件 http 日志 中的 headers   这个配置生效的前提是  hideServerMiddleLog=false
 }
 
+// NewEcho 创建 Echo 实例并挂载默认实例的所有路由（见 Server.NewEcho）
 func NewEcho(middlewares ...echo.MiddlewareFunc) *echo.Echo {
-	r := echo.New()
-	var baseMiddleWares = []echo.MiddlewareFunc{
-		//BaseErrorMiddleware(),
-		//CorsMiddleware(corsConfig),
-		//EchoLogger(config.HideServerMiddleLog, config.HideServerMiddleLogHeaders),
-	}
-	if len(middlewares) > 0 {
-		baseMiddleWares = append(baseMiddleWares, middlewares...)
-	}
-	r.Use(baseMiddleWares...)
-	MountRoutes(r)
-	return r
+	return defaultServer.NewEcho(middlewares...)
 }
@@ -0,0 +1,147 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/preceeder/echoApi"
+)
+
+// ==================== body 绑定基准测试（进程内，不需要启动服务器） ====================
+
+type benchItem struct {
+	SkuID uint64 `json:"skuId"`
+	Count int    `json:"count"`
+	Price string `json:"price"`
+}
+
+type benchOrderReq struct {
+	OrderID int64       `json:"orderId"`
+	UserID  int64       `json:"userId"`
+	Remark  string      `json:"remark"`
+	Items   []benchItem `json:"items"`
+	Source  string      `query:"source"`
+}
+
+type benchCtrl struct{}
+
+func (b *benchCtrl) RouteConfig() echoApi.RouteConfig {
+	return echoApi.RouteConfig{
+		POST: []echoApi.RouteBuilder{
+			{Path: "/bench/order", FuncName: "Order"},
+		},
+	}
+}
+
+func (b *benchCtrl) Order(c echo.Context, req *benchOrderReq) echoApi.HttpResponse {
+	return echoApi.BaseHttpResponse{Data: req.OrderID}
+}
+
+const benchOrderBody = `{"orderId":9007199254740993,"userId":9223372036854775807,"remark":"bench",` +
+	`"items":[{"skuId":18446744073709551615,"count":2,"price":"9.90"},{"skuId":1,"count":1,"price":"0.01"}]}`
+
+// benchOrderTyped 泛型 handler 版本
+func benchOrderTyped(c echo.Context, req *benchOrderReq) (echoApi.HttpResponse, error) {
+	return echoApi.BaseHttpResponse{Data: req.OrderID}, nil
+}
+
+var (
+	benchEchoOnce sync.Once
+	benchEcho     *echo.Echo
+
+	benchTypedEchoOnce sync.Once
+	benchTypedEcho     *echo.Echo
+)
+
+func newBenchEcho(tb testing.TB) *echo.Echo {
+	benchEchoOnce.Do(func() {
+		if err := echoApi.Register(&benchCtrl{}); err != nil {
+			tb.Fatal(err)
+		}
+		benchEcho = echoApi.NewEcho(echoApi.BaseErrorMiddleware(), echoApi.EchoResponseAndRecoveryHandler(nil, nil))
+	})
+	return benchEcho
+}
+
+func newBenchTypedEcho(tb testing.TB) *echo.Echo {
+	benchTypedEchoOnce.Do(func() {
+		srv := echoApi.NewServer("/api")
+		if err := echoApi.Handle(srv, echoApi.RouteBuilder{Method: http.MethodPost, Path: "/bench/order"}, benchOrderTyped); err != nil {
+			tb.Fatal(err)
+		}
+		benchTypedEcho = srv.NewEcho(echoApi.BaseErrorMiddleware(), echoApi.EchoResponseAndRecoveryHandler(nil, nil))
+	})
+	return benchTypedEcho
+}
+
+// BenchmarkBindJSONBody 完整请求链路的 body 绑定（Controller 方式，反射调用 handler）
+func BenchmarkBindJSONBody(b *testing.B) {
+	benchmarkBindJSONBody(b, newBenchEcho(b))
+}
+
+// BenchmarkBindJSONBodyTyped 完整请求链路的 body 绑定（泛型 handler，不经过 reflect.Value.Call）
+func BenchmarkBindJSONBodyTyped(b *testing.B) {
+	benchmarkBindJSONBody(b, newBenchTypedEcho(b))
+}
+
+func benchmarkBindJSONBody(b *testing.B, e *echo.Echo) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/api/bench/order?source=app", strings.NewReader(benchOrderBody))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			b.Fatalf("状态码错误: %d %s", rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestBindJSONBodyLargeID 大整数 ID 经过绑定后不丢失精度
+func TestBindJSONBodyLargeID(t *testing.T) {
+	e := newBenchEcho(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/bench/order", strings.NewReader(benchOrderBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"data":9007199254740993`) {
+		t.Fatalf("大整数精度丢失: %s", rec.Body.String())
+	}
+}
+
+// BenchmarkDecodeLegacyMap 旧实现：先解码到 map[string]interface{}，再逐个字段 Marshal/Unmarshal
+func BenchmarkDecodeLegacyMap(b *testing.B) {
+	body := []byte(benchOrderBody)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var bodyMap map[string]interface{}
+		if err := json.Unmarshal(body, &bodyMap); err != nil {
+			b.Fatal(err)
+		}
+		var req benchOrderReq
+		req.OrderID = int64(bodyMap["orderId"].(float64))
+		req.UserID = int64(bodyMap["userId"].(float64))
+		req.Remark = bodyMap["remark"].(string)
+		items, _ := json.Marshal(bodyMap["items"])
+		_ = json.Unmarshal(items, &req.Items)
+	}
+}
+
+// BenchmarkDecodeDirect 新实现：直接解码到参数结构体
+func BenchmarkDecodeDirect(b *testing.B) {
+	body := []byte(benchOrderBody)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var req benchOrderReq
+		if err := json.Unmarshal(body, &req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package echoApi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrRouteNotFound URLFor 找不到该路由名
+var ErrRouteNotFound = errors.New("路由名不存在")
+
+// findRouteNameConflict 同名路由的路径必须相同（同一路径的多个方法、版本可以共用路由名）
+func findRouteNameConflict(route Route, routeLists ...[]Route) error {
+	if route.Name == "" {
+		return nil
+	}
+	for _, routes := range routeLists {
+		for _, other := range routes {
+			if other.Name != route.Name {
+				continue
+			}
+			if other.Path != route.Path || other.NoUseBasePrefixPath != route.NoUseBasePrefixPath {
+				return fmt.Errorf("%w: %s 已用于 %s %s", ErrDuplicateName, route.Name, other.Method, other.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// URLFor 按路由名生成 URL，路由前缀规则与 MountRoutes 一致
+// params 为 key/value 对：key 与路径参数（:id 或 *）同名时替换路径参数，其余按顺序作为 query 参数追加
+//
+//	URLFor("user.info", "id", 1, "tab", "profile") // /api/user/1?tab=profile
+func (s *Server) URLFor(name string, params ...any) (string, error) {
+	return s.urlFor(s.basePrefixPath(), name, params...)
+}
+
+func (s *Server) urlFor(prefix, name string, params ...any) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("URLFor %s: params 必须是 key/value 对", name)
+	}
+
+	s.mu.RLock()
+	var route *Route
+	for i := range s.routes {
+		if s.routes[i].Name == name {
+			route = &s.routes[i]
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if route == nil {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+
+	values := make(map[string]string, len(params)/2)
+	var keys []string
+	for i := 0; i < len(params); i += 2 {
+		key := fmt.Sprint(params[i])
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = fmt.Sprint(params[i+1])
+	}
+
+	segments := strings.Split(route.Path, "/")
+	for i, seg := range segments {
+		var key string
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			key = seg[1:]
+		case seg == "*":
+			key = "*"
+		default:
+			continue
+		}
+		value, ok := values[key]
+		if !ok {
+			return "", fmt.Errorf("URLFor %s: 缺少路径参数 %s", name, key)
+		}
+		if key == "*" {
+			// 通配符可以包含多级路径
+			parts := strings.Split(value, "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+		delete(values, key)
+	}
+
+	var b strings.Builder
+	b.WriteString(routeBasePath(prefix, *route))
+	b.WriteString(strings.Join(segments, "/"))
+	sep := "?"
+	for _, key := range keys {
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+		b.WriteString(sep + url.QueryEscape(key) + "=" + url.QueryEscape(value))
+		sep = "&"
+	}
+	return b.String(), nil
+}
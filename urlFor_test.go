@@ -0,0 +1,59 @@
+package echoApi
+
+import (
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNamedCtrl struct{}
+
+func (t *testNamedCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/user/:id/post/:postId", FuncName: "Name", Name: "user.post"},
+			{Path: "/files/*", FuncName: "Name", Name: "files"},
+			{Path: "/raw", FuncName: "Name", Name: "raw", NoUseBasePrefixPath: true},
+			{Path: "/other", FuncName: "Name", Name: "files"},
+		},
+		Custom: []RouteBuilder{
+			{Methods: []string{"PUT", "POST"}, Path: "/user/:id", FuncName: "Name", Name: "user"},
+		},
+	}
+}
+
+func (t *testNamedCtrl) Name(c echo.Context) HttpResponse {
+	return nil
+}
+
+func TestURLFor(t *testing.T) {
+	srv := NewServer("/api/")
+	err := srv.Group("/v1").Register(&testNamedCtrl{})
+	// 同名不同路径的路由被拒绝，同一路径的多个方法可以共用路由名
+	assert.ErrorIs(t, err, ErrDuplicateName)
+	assert.NotContains(t, err.Error(), "/user/:id ")
+
+	url, err := srv.URLFor("user.post", "id", 12, "postId", "a b", "tab", "x&y", "page", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/user/12/post/a%20b?tab=x%26y&page=2", url)
+
+	url, err = srv.URLFor("files", "*", "a/b c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/files/a/b%20c.txt", url)
+
+	url, err = srv.URLFor("raw")
+	assert.NoError(t, err)
+	assert.Equal(t, "/v1/raw", url)
+
+	url, err = srv.URLFor("user", "id", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/user/1", url)
+
+	_, err = srv.URLFor("user.post", "id", 1)
+	assert.ErrorContains(t, err, "postId")
+	_, err = srv.URLFor("user", "id")
+	assert.Error(t, err)
+	_, err = srv.URLFor("none")
+	assert.ErrorIs(t, err, ErrRouteNotFound)
+}
@@ -0,0 +1,432 @@
+package echoApi
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 校验规则写在 binding 标签中，多个规则用逗号分隔，例如：
+//
+//	Email string `json:"email" binding:"required,email"`
+//	Age   int    `json:"age" binding:"min=1,max=150"`
+//	Role  string `json:"role" binding:"omitempty,oneof=admin user"`
+//	Code  string `json:"code" binding:"regex=^[A-Z]{2}[0-9]{4}$"`
+//
+// 支持的规则：required、omitempty、min、max、len、oneof（空格分隔）、regex、email、url。
+// regex 会吞掉标签剩余的全部内容，因此必须放在最后；标签值会按 Go 字符串转义，反斜杠需写成 \\。
+// 结构体（及其指针、切片）类型的字段会递归校验，binding:"-" 可跳过该字段。
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field" xml:"field"`                     // 字段路径，如 user.email、items[0].name
+	Rule    string `json:"rule" xml:"rule"`                       // 未通过的规则
+	Param   string `json:"param,omitempty" xml:"param,omitempty"` // 规则参数
+	Message string `json:"message" xml:"message"`
+
+	code string // 消息的错误码（如 VALIDATION_MIN），响应时按 Accept-Language 翻译
+	args []any
+}
+
+// ValidationErrors 一次请求中所有字段的校验错误
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, fe := range v {
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// validateRule 编译后的单条规则
+type validateRule struct {
+	Name        string
+	Param       string
+	Check       func(v reflect.Value) bool
+	Message     string
+	MessageCode string // 消息的错误码，Message 为其 DefaultLanguage 下的消息
+	MessageArgs []any
+}
+
+// setMessage 按错误码设置规则的消息
+func (r *validateRule) setMessage(code string, args ...any) {
+	r.MessageCode = code
+	r.MessageArgs = args
+	r.Message = errorMessage(code, args...)
+}
+
+// fieldValidator 单个字段的校验信息（使用字段索引，避免运行时 FieldByName 查找）
+type fieldValidator struct {
+	FieldIndex int
+	Name       string // 展示名（优先使用标签名）
+	Embedded   bool   // 匿名字段，子字段不加前缀
+	OmitEmpty  bool
+	Rules      []validateRule
+	Nested     *structValidator // 结构体、结构体指针或结构体切片的元素校验
+}
+
+// structValidator 结构体的校验信息，在 Register 时构建一次
+type structValidator struct {
+	Fields []fieldValidator
+}
+
+// buildStructValidator 编译结构体的校验规则，没有任何规则时返回 nil
+func buildStructValidator(t reflect.Type) (*structValidator, error) {
+	return buildStructValidatorWithSeen(t, map[reflect.Type]bool{})
+}
+
+func buildStructValidatorWithSeen(t reflect.Type, seen map[reflect.Type]bool) (*structValidator, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil, nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []fieldValidator
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("binding")
+		if tag == "-" {
+			continue
+		}
+
+		fv := fieldValidator{
+			FieldIndex: i,
+			Name:       fieldDisplayName(field),
+			Embedded:   field.Anonymous,
+		}
+
+		rules, omitEmpty, err := parseRules(tag, field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("字段 %s 校验规则错误: %w", field.Name, err)
+		}
+		fv.Rules = rules
+		fv.OmitEmpty = omitEmpty
+
+		if nestedType := nestedStructType(field.Type); nestedType != nil {
+			nested, err := buildStructValidatorWithSeen(nestedType, seen)
+			if err != nil {
+				return nil, fmt.Errorf("字段 %s: %w", field.Name, err)
+			}
+			fv.Nested = nested
+		}
+
+		if len(fv.Rules) > 0 || fv.Nested != nil {
+			fields = append(fields, fv)
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return &structValidator{Fields: fields}, nil
+}
+
+// nestedStructType 返回需要递归校验的结构体类型（支持 T、*T、[]T、[]*T）
+func nestedStructType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && !isScalarStruct(t) {
+		return t
+	}
+	return nil
+}
+
+// isScalarStruct 像 time.Time 这类整体作为值使用的结构体，不递归校验
+func isScalarStruct(t reflect.Type) bool {
+	return t.PkgPath() == "time"
+}
+
+// fieldDisplayName 字段展示名：json > form > query > param > header > 字段名
+func fieldDisplayName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query", "param", "header", "cookie"} {
+		if tag := field.Tag.Get(key); tag != "" && tag != "-" {
+			if name := strings.Split(tag, ",")[0]; name != "" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+// parseRules 解析 binding 标签
+func parseRules(tag string, t reflect.Type) ([]validateRule, bool, error) {
+	if tag == "" {
+		return nil, false, nil
+	}
+
+	var rules []validateRule
+	omitEmpty := false
+	for tag != "" {
+		var item string
+		if strings.HasPrefix(tag, "regex=") {
+			// regex 吞掉剩余内容，避免正则中的逗号被拆分
+			item, tag = tag, ""
+		} else if idx := strings.Index(tag, ","); idx >= 0 {
+			item, tag = tag[:idx], tag[idx+1:]
+		} else {
+			item, tag = tag, ""
+		}
+
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if item == "omitempty" {
+			omitEmpty = true
+			continue
+		}
+
+		name, param, _ := strings.Cut(item, "=")
+		rule, err := compileRule(name, param, t)
+		if err != nil {
+			return nil, false, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, omitEmpty, nil
+}
+
+// compileRule 编译单条规则
+func compileRule(name, param string, t reflect.Type) (validateRule, error) {
+	rule := validateRule{Name: name, Param: param}
+
+	switch name {
+	case "required":
+		rule.Check = func(v reflect.Value) bool { return !isZero(v) }
+		rule.setMessage("VALIDATION_REQUIRED")
+
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return rule, fmt.Errorf("规则 %s 的参数必须是数字: %q", name, param)
+		}
+		isLength := hasLength(indirectType(t))
+		switch name {
+		case "min":
+			rule.Check = func(v reflect.Value) bool { n, ok := measure(v); return !ok || n >= limit }
+			rule.setMessage("VALIDATION_MIN", param)
+			if isLength {
+				rule.setMessage("VALIDATION_MIN_LENGTH", param)
+			}
+		case "max":
+			rule.Check = func(v reflect.Value) bool { n, ok := measure(v); return !ok || n <= limit }
+			rule.setMessage("VALIDATION_MAX", param)
+			if isLength {
+				rule.setMessage("VALIDATION_MAX_LENGTH", param)
+			}
+		case "len":
+			rule.Check = func(v reflect.Value) bool { n, ok := measure(v); return !ok || n == limit }
+			rule.setMessage("VALIDATION_LEN", param)
+		}
+
+	case "oneof":
+		options := strings.Fields(param)
+		if len(options) == 0 {
+			return rule, fmt.Errorf("规则 oneof 至少需要一个可选值")
+		}
+		rule.Check = func(v reflect.Value) bool {
+			s, ok := scalarString(v)
+			if !ok {
+				return true
+			}
+			for _, o := range options {
+				if s == o {
+					return true
+				}
+			}
+			return false
+		}
+		rule.setMessage("VALIDATION_ONEOF", strings.Join(options, " "))
+
+	case "regex":
+		re, err := regexp.Compile(param)
+		if err != nil {
+			return rule, fmt.Errorf("规则 regex 的正则表达式无效: %w", err)
+		}
+		rule.Check = func(v reflect.Value) bool {
+			s, ok := stringValue(v)
+			return !ok || re.MatchString(s)
+		}
+		rule.setMessage("VALIDATION_REGEX")
+
+	case "email":
+		rule.Check = func(v reflect.Value) bool {
+			s, ok := stringValue(v)
+			if !ok {
+				return true
+			}
+			addr, err := mail.ParseAddress(s)
+			return err == nil && addr.Address == s
+		}
+		rule.setMessage("VALIDATION_EMAIL")
+
+	case "url":
+		rule.Check = func(v reflect.Value) bool {
+			s, ok := stringValue(v)
+			if !ok {
+				return true
+			}
+			u, err := url.ParseRequestURI(s)
+			return err == nil && u.Scheme != "" && u.Host != ""
+		}
+		rule.setMessage("VALIDATION_URL")
+
+	default:
+		return rule, fmt.Errorf("不支持的校验规则: %s", name)
+	}
+
+	return rule, nil
+}
+
+// validate 校验结构体值，返回全部字段错误
+func (sv *structValidator) validate(v reflect.Value, prefix string) ValidationErrors {
+	if sv == nil {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	var errs ValidationErrors
+	for i := range sv.Fields {
+		fv := &sv.Fields[i]
+		fieldValue := v.Field(fv.FieldIndex)
+
+		path := prefix
+		if !fv.Embedded {
+			path = joinFieldPath(prefix, fv.Name)
+		}
+
+		if fv.OmitEmpty && isZero(fieldValue) {
+			continue
+		}
+
+		for _, rule := range fv.Rules {
+			if !rule.Check(fieldValue) {
+				errs = append(errs, FieldError{
+					Field:   path,
+					Rule:    rule.Name,
+					Param:   rule.Param,
+					Message: rule.Message,
+					code:    rule.MessageCode,
+					args:    rule.MessageArgs,
+				})
+			}
+		}
+
+		if fv.Nested == nil {
+			continue
+		}
+		switch fieldValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fieldValue.Len(); j++ {
+				errs = append(errs, fv.Nested.validate(fieldValue.Index(j), fmt.Sprintf("%s[%d]", path, j))...)
+			}
+		default:
+			errs = append(errs, fv.Nested.validate(fieldValue, path)...)
+		}
+	}
+	return errs
+}
+
+func joinFieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func hasLength(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// measure 返回用于 min/max/len 比较的量：数字取值，字符串取字符数，集合取长度
+// nil 指针返回 ok=false（由 required 负责）
+func measure(v reflect.Value) (float64, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	}
+	return 0, false
+}
+
+// stringValue 取字符串值（支持字符串指针）
+func stringValue(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// scalarString 将字符串和数字统一格式化为字符串，用于 oneof 比较
+func scalarString(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
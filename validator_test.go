@@ -0,0 +1,94 @@
+package echoApi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validateAddress struct {
+	City string `json:"city" binding:"required"`
+	Zip  string `json:"zip" binding:"omitempty,len=6"`
+}
+
+type validateReq struct {
+	Username string            `json:"username" binding:"required,min=3,max=8"`
+	Email    string            `json:"email" binding:"required,email"`
+	Site     string            `json:"site" binding:"omitempty,url"`
+	Age      int               `json:"age" binding:"min=1,max=150"`
+	Role     string            `json:"role" binding:"oneof=admin user"`
+	Code     string            `json:"code" binding:"regex=^[A-Z]{2}\\d{2,4}$"`
+	Tags     []string          `json:"tags" binding:"max=2"`
+	Address  *validateAddress  `json:"address" binding:"required"`
+	Others   []validateAddress `json:"others"`
+	Page     int               `query:"page" binding:"min=1"`
+}
+
+func TestStructValidator(t *testing.T) {
+	sv, err := buildStructValidator(reflect.TypeOf(validateReq{}))
+	assert.NoError(t, err)
+
+	valid := validateReq{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Site:     "https://example.com",
+		Age:      20,
+		Role:     "admin",
+		Code:     "AB123",
+		Tags:     []string{"a"},
+		Address:  &validateAddress{City: "sh"},
+		Others:   []validateAddress{{City: "bj", Zip: "100000"}},
+		Page:     1,
+	}
+	assert.Empty(t, sv.validate(reflect.ValueOf(valid), ""))
+
+	invalid := validateReq{
+		Username: "中文",
+		Email:    "Alice <alice@example.com>",
+		Site:     "example.com",
+		Role:     "guest",
+		Code:     "A,1",
+		Tags:     []string{"a", "b", "c"},
+		Others:   []validateAddress{{City: "bj"}, {Zip: "1"}},
+	}
+	errs := sv.validate(reflect.ValueOf(&invalid), "")
+
+	var got []string
+	for _, fe := range errs {
+		got = append(got, fe.Field+":"+fe.Rule)
+	}
+	assert.Equal(t, []string{
+		"username:min",
+		"email:email",
+		"site:url",
+		"age:min",
+		"role:oneof",
+		"code:regex",
+		"tags:max",
+		"address:required",
+		"others[1].city:required",
+		"others[1].zip:len",
+		"page:min",
+	}, got)
+}
+
+func TestStructValidator_NoRules(t *testing.T) {
+	sv, err := buildStructValidator(reflect.TypeOf(DefaultHeader{}))
+	assert.NoError(t, err)
+	assert.Nil(t, sv)
+}
+
+func TestStructValidator_InvalidRule(t *testing.T) {
+	type badReq struct {
+		Name string `json:"name" binding:"required,unknown"`
+	}
+	_, err := buildStructValidator(reflect.TypeOf(badReq{}))
+	assert.Error(t, err)
+
+	type badLimit struct {
+		Name string `json:"name" binding:"min=abc"`
+	}
+	_, err = buildStructValidator(reflect.TypeOf(badLimit{}))
+	assert.Error(t, err)
+}
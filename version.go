@@ -0,0 +1,203 @@
+package echoApi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// VersionConfig API 版本选择方式
+// 优先级：路径版本 > 请求头 > Accept 厂商媒体类型 > Default > 最新版本
+type VersionConfig struct {
+	PathPrefix string // 路径版本前缀，如 "v" 时 2 版本额外挂载到 /api/v2/...，为空表示不使用路径版本
+	Header     string // 指定版本的请求头，如 x-auth-version（可以直接传客户端版本号，如 2.3.1，按最新兼容版本匹配）
+	Vendor     string // Accept 厂商媒体类型，如 myapp 时匹配 application/vnd.myapp.v2+json 或 application/vnd.myapp+json; version=2
+	Default    string // 请求未指定版本时使用的版本，为空表示最新版本
+}
+
+// DefaultVersionConfig 默认的版本选择方式（Server.Versioning 为 nil 时使用）
+var DefaultVersionConfig = VersionConfig{PathPrefix: "v", Header: "x-auth-version"}
+
+// versioning 实例的版本选择方式
+func (s *Server) versioning() VersionConfig {
+	if s.Versioning != nil {
+		return *s.Versioning
+	}
+	return DefaultVersionConfig
+}
+
+// versionPath 版本路由额外挂载的路径版本，如 /api/v2/user/info；不挂载时返回空
+func (config VersionConfig) versionPath(basePath string, route Route) string {
+	if config.PathPrefix == "" || route.Version == "" || strings.ToUpper(route.Method) == "WS" {
+		return ""
+	}
+	v, ok := parseVersion(route.Version)
+	if !ok {
+		return ""
+	}
+	return basePath + "/" + config.PathPrefix + v.String() + route.Path
+}
+
+// apiVersion 解析后的版本号，如 2.1 -> [2 1]
+type apiVersion []int
+
+// parseVersion 解析版本号，支持 v 前缀和任意段数，如 2、v2、2.1、2.3.1
+func parseVersion(s string) (apiVersion, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	if s == "" {
+		return nil, false
+	}
+	parts := strings.Split(s, ".")
+	v := make(apiVersion, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// String 规范化的版本号，如 V2.01 -> 2.1
+func (v apiVersion) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// compare 比较版本号，缺少的段按 0 处理（2 == 2.0）
+func (v apiVersion) compare(o apiVersion) int {
+	for i := 0; i < len(v) || i < len(o); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionDispatcher 同一方法和路径的多个版本，按请求的版本分发
+type versionDispatcher struct {
+	method   string
+	path     string
+	config   VersionConfig
+	handlers []versionedHandler // 按版本从低到高排序
+}
+
+type versionedHandler struct {
+	version apiVersion
+	handler echo.HandlerFunc
+}
+
+func (d *versionDispatcher) add(version string, handler echo.HandlerFunc) {
+	v, _ := parseVersion(version)
+	d.handlers = append(d.handlers, versionedHandler{version: v, handler: handler})
+	sort.SliceStable(d.handlers, func(i, j int) bool {
+		return d.handlers[i].version.compare(d.handlers[j].version) < 0
+	})
+}
+
+// match 返回不高于 requested 的最新版本（最新兼容版本）
+func (d *versionDispatcher) match(requested apiVersion) echo.HandlerFunc {
+	for i := len(d.handlers) - 1; i >= 0; i-- {
+		if d.handlers[i].version.compare(requested) <= 0 {
+			return d.handlers[i].handler
+		}
+	}
+	return nil
+}
+
+func (d *versionDispatcher) handle(c echo.Context) error {
+	raw := requestedVersion(c.Request(), d.config)
+	if raw == "" {
+		raw = d.config.Default
+	}
+	if raw == "" {
+		return d.handlers[len(d.handlers)-1].handler(c)
+	}
+
+	if requested, ok := parseVersion(raw); ok {
+		if handler := d.match(requested); handler != nil {
+			return handler(c)
+		}
+	}
+
+	requestId := RequestID(Context(c))
+	htperr := NewError("UNSUPPORTED_VERSION", raw)
+	htperr.RequestId = requestId
+	return writeResponse(c, htperr.StatusCode, responseBody(c, htperr, requestId))
+}
+
+// requestedVersion 从请求头或 Accept 厂商媒体类型中取请求的版本
+func requestedVersion(r *http.Request, config VersionConfig) string {
+	if config.Header != "" {
+		if v := strings.TrimSpace(r.Header.Get(config.Header)); v != "" {
+			return v
+		}
+	}
+	if config.Vendor != "" {
+		if v := vendorVersion(r.Header.Get(echo.HeaderAccept), config.Vendor); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// vendorVersion 解析 Accept 中的厂商媒体类型版本：
+// application/vnd.myapp.v2+json 或 application/vnd.myapp+json; version=2
+func vendorVersion(accept, vendor string) string {
+	prefix := "application/vnd." + strings.ToLower(vendor)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		rest, ok := strings.CutPrefix(strings.ToLower(strings.TrimSpace(mediaType)), prefix)
+		if !ok {
+			continue
+		}
+		rest, _, _ = strings.Cut(rest, "+")
+		if v, ok := strings.CutPrefix(rest, ".v"); ok && v != "" {
+			return v
+		}
+		for _, param := range strings.Split(params, ";") {
+			if key, val, ok := strings.Cut(strings.TrimSpace(param), "="); ok && strings.TrimSpace(key) == "version" {
+				return strings.Trim(strings.TrimSpace(val), `"`)
+			}
+		}
+	}
+	return ""
+}
+
+// withVersionHeaders 已废弃的版本在响应中带上 Deprecation / Sunset 头
+func withVersionHeaders(route Route, handler echo.HandlerFunc) echo.HandlerFunc {
+	if !route.Deprecated && route.Sunset.IsZero() {
+		return handler
+	}
+	sunset := ""
+	if !route.Sunset.IsZero() {
+		sunset = route.Sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(c echo.Context) error {
+		header := c.Response().Header()
+		if route.Deprecated {
+			header.Set("Deprecation", "true")
+		}
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		return handler(c)
+	}
+}
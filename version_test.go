@@ -0,0 +1,179 @@
+package echoApi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testUserV1Ctrl struct{}
+
+func (t *testUserV1Ctrl) RouteConfig() RouteConfig {
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	return RouteConfig{
+		Global: &RouteBuilder{Version: "1", Deprecated: true, Sunset: sunset},
+		GET: []RouteBuilder{
+			{Path: "/user/info", FuncName: "Info"},
+		},
+	}
+}
+
+func (t *testUserV1Ctrl) Info(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "v1"}
+}
+
+type testUserV2Ctrl struct{}
+
+func (t *testUserV2Ctrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/user/info", FuncName: "Info", Version: "2"},
+			{Path: "/user/info", FuncName: "InfoV21", Version: "2.1"},
+		},
+	}
+}
+
+func (t *testUserV2Ctrl) Info(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "v2"}
+}
+
+func (t *testUserV2Ctrl) InfoV21(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "v2.1"}
+}
+
+type testVersionPathCtrl struct{}
+
+func (t *testVersionPathCtrl) RouteConfig() RouteConfig {
+	return RouteConfig{
+		GET: []RouteBuilder{
+			{Path: "/user/info", FuncName: "Info", Version: "V2"},
+			{Path: "/v2/user/info", FuncName: "Plain"},
+			{Path: "/api/v2/user/info", FuncName: "Plain", NoUseBasePrefixPath: true},
+		},
+	}
+}
+
+func (t *testVersionPathCtrl) Info(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "v2"}
+}
+
+func (t *testVersionPathCtrl) Plain(c echo.Context) HttpResponse {
+	return BaseHttpResponse{Data: "plain"}
+}
+
+func TestParseVersion(t *testing.T) {
+	v, ok := parseVersion("v2.3.1")
+	assert.True(t, ok)
+	assert.Equal(t, apiVersion{2, 3, 1}, v)
+	assert.Equal(t, 0, apiVersion{2}.compare(apiVersion{2, 0}))
+	assert.Equal(t, -1, apiVersion{2}.compare(apiVersion{2, 1}))
+	assert.Equal(t, 1, apiVersion{10}.compare(apiVersion{9, 9}))
+
+	v, ok = parseVersion("V2.01")
+	assert.True(t, ok)
+	assert.Equal(t, "2.1", v.String())
+
+	for _, bad := range []string{"", "v", "1.x", "-1"} {
+		_, ok = parseVersion(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestVendorVersion(t *testing.T) {
+	assert.Equal(t, "2", vendorVersion("application/vnd.myapp.v2+json", "myapp"))
+	assert.Equal(t, "3", vendorVersion("text/html, application/vnd.myapp+json; version=3", "myapp"))
+	assert.Equal(t, "", vendorVersion("application/vnd.other.v2+json", "myapp"))
+	assert.Equal(t, "", vendorVersion("application/json", "myapp"))
+}
+
+func TestVersioning(t *testing.T) {
+	srv := NewServer("/api")
+	srv.Versioning = &VersionConfig{PathPrefix: "v", Header: "x-auth-version", Vendor: "myapp"}
+	assert.NoError(t, srv.Register(&testUserV1Ctrl{}))
+	assert.NoError(t, srv.Register(&testUserV2Ctrl{}))
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+
+	request := func(target string, header map[string]string) (*httptest.ResponseRecorder, any) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var res map[string]any
+		_ = JSONCodec{}.Unmarshal(rec.Body.Bytes(), &res)
+		return rec, res["data"]
+	}
+
+	// 路径版本
+	rec, data := request("/api/v1/user/info", nil)
+	assert.Equal(t, "v1", data)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+	_, data = request("/api/v2.1/user/info", nil)
+	assert.Equal(t, "v2.1", data)
+	rec, _ = request("/api/v3/user/info", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// 未指定版本使用最新版本
+	rec, data = request("/api/user/info", nil)
+	assert.Equal(t, "v2.1", data)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+
+	// 请求头，回退到最新兼容版本
+	_, data = request("/api/user/info", map[string]string{"x-auth-version": "2.0.9"})
+	assert.Equal(t, "v2", data)
+	_, data = request("/api/user/info", map[string]string{"x-auth-version": "5.1"})
+	assert.Equal(t, "v2.1", data)
+	rec, data = request("/api/user/info", map[string]string{"x-auth-version": "1.4.2"})
+	assert.Equal(t, "v1", data)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+
+	// Accept 厂商媒体类型
+	rec, data = request("/api/user/info", map[string]string{echo.HeaderAccept: "application/vnd.myapp.v2+json"})
+	assert.Equal(t, "v2", data)
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+
+	// 没有兼容的版本
+	rec, _ = request("/api/user/info", map[string]string{"x-auth-version": "0.9"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = request("/api/user/info", map[string]string{"x-auth-version": "abc"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Default 版本
+	srv.Versioning.Default = "1"
+	e = srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+	_, data = request("/api/user/info", nil)
+	assert.Equal(t, "v1", data)
+}
+
+func TestVersionPath_Conflict(t *testing.T) {
+	srv := NewServer("/api")
+	err := srv.Register(&testVersionPathCtrl{})
+	assert.ErrorIs(t, err, ErrDuplicateRoute)
+	var got []string
+	for _, re := range routeErrors(err) {
+		got = append(got, re.FuncName+" "+re.Method+" "+re.Path)
+	}
+	assert.Equal(t, []string{"Plain GET /v2/user/info"}, got)
+
+	// 加上前缀后才与路径版本重复
+	err = srv.MountRoutes(echo.New())
+	assert.ErrorIs(t, err, ErrDuplicateRoute)
+	got = nil
+	for _, re := range routeErrors(err) {
+		got = append(got, re.FuncName+" "+re.Method+" "+re.Path)
+	}
+	assert.Equal(t, []string{"Plain GET /api/v2/user/info"}, got)
+
+	// 版本号规范化后挂载，V2 对应 /api/v2
+	e := srv.NewEcho(BaseErrorMiddleware(), EchoResponseAndRecoveryHandler(nil, nil))
+	rec, res := doTestRequest(e, http.MethodGet, "/api/v2/user/info", nil, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v2", res["data"])
+	assert.Equal(t, "/api/v2/user/info", srv.Routes()[0].VersionPath)
+}